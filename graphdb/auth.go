@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// authenticator adds credentials to the requests issued by the Client.
+type authenticator interface {
+	// authenticate sets the credentials on the outgoing request.
+	authenticate(ctx context.Context, c *Client, req *http.Request) error
+	// invalidate discards the credentials used for req, after the server rejected them.
+	// It returns true when fresh credentials can be obtained and the request is worth retrying.
+	invalidate(req *http.Request) bool
+}
+
+type basicAuthenticator struct {
+	username string
+	password string
+}
+
+func (b *basicAuthenticator) authenticate(_ context.Context, _ *Client, req *http.Request) error {
+	req.SetBasicAuth(b.username, b.password)
+	return nil
+}
+
+func (b *basicAuthenticator) invalidate(_ *http.Request) bool {
+	return false
+}
+
+// tokenAuthenticator logs in via the GraphDB login endpoint and sends the resulting GDB token with each request.
+// The token is cached until the server rejects it, at which point the next request logs in again.
+type tokenAuthenticator struct {
+	username string
+	password string
+
+	mu    sync.Mutex
+	token string
+}
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (t *tokenAuthenticator) authenticate(ctx context.Context, c *Client, req *http.Request) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == "" {
+		token, err := t.login(ctx, c, c.nodeFor(req.URL))
+		if err != nil {
+			return err
+		}
+		t.token = token
+	}
+	req.Header.Set("Authorization", t.token)
+	return nil
+}
+
+func (t *tokenAuthenticator) invalidate(req *http.Request) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Only discard the token if another request hasn't already refreshed it
+	if t.token == req.Header.Get("Authorization") {
+		t.token = ""
+	}
+	return true
+}
+
+// login obtains a token from the given node. The nodes of a cluster share their token secret,
+// so the token is accepted by every node.
+func (t *tokenAuthenticator) login(ctx context.Context, c *Client, node *url.URL) (string, error) {
+	body, err := json.Marshal(loginRequest{Username: t.username, Password: t.password})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", node.JoinPath("rest", "login").String(), bytes.NewBuffer(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	if err := checkResponse(resp, http.StatusOK); err != nil {
+		return "", fmt.Errorf("Failed to login as %s: %w", t.username, err)
+	}
+	defer resp.Body.Close()
+
+	token := resp.Header.Get("Authorization")
+	if token == "" {
+		return "", fmt.Errorf("Login as %s did not return an authorization token", t.username)
+	}
+	return token, nil
+}
+
+// bearerAuthenticator sends a static bearer token, such as one issued by an OpenID Connect provider.
+type bearerAuthenticator struct {
+	token string
+}
+
+func (b *bearerAuthenticator) authenticate(_ context.Context, _ *Client, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+b.token)
+	return nil
+}
+
+func (b *bearerAuthenticator) invalidate(_ *http.Request) bool {
+	return false
+}
+
+// oauth2Authenticator obtains bearer tokens using the OAuth2 client credentials flow.
+// Tokens are cached and refreshed shortly before they expire, or when the server rejects them.
+type oauth2Authenticator struct {
+	config clientcredentials.Config
+
+	mu     sync.Mutex
+	source oauth2.TokenSource
+}
+
+func (o *oauth2Authenticator) authenticate(_ context.Context, c *Client, req *http.Request) error {
+	o.mu.Lock()
+	if o.source == nil {
+		// The token source outlives any single request, so it must not be bound to the request context.
+		// Tokens are requested with the client's own HTTP client and retry policy, so that they use the same TLS and
+		// proxy settings and survive the same transient failures.
+		tokens := *c.client
+		tokens.Transport = &retryTransport{next: c.client.Transport, policy: c.retry}
+		o.source = o.config.TokenSource(context.WithValue(context.Background(), oauth2.HTTPClient, &tokens))
+	}
+	source := o.source
+	o.mu.Unlock()
+
+	token, err := source.Token()
+	if err != nil {
+		return fmt.Errorf("Failed to obtain OAuth2 token from %s. Error: %w", o.config.TokenURL, err)
+	}
+	token.SetAuthHeader(req)
+	return nil
+}
+
+func (o *oauth2Authenticator) invalidate(_ *http.Request) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.source = nil
+	return true
+}
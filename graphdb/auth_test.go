@@ -0,0 +1,211 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// newTokenTestServer returns a server which issues a new GDB token on every login and only accepts the latest one.
+func newTokenTestServer(t *testing.T) (*httptest.Server, *int) {
+	t.Helper()
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/login" {
+			var login loginRequest
+			if err := json.NewDecoder(r.Body).Decode(&login); err != nil || login.Password != "root" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			logins++
+			w.Header().Set("Authorization", fmt.Sprintf("GDB token-%d", logins))
+			return
+		}
+		if r.Header.Get("Authorization") != fmt.Sprintf("GDB token-%d", logins) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &logins
+}
+
+func TestTokenAuthLogsInOnce(t *testing.T) {
+	server, logins := newTokenTestServer(t)
+	client := newTestClient(t, server.URL, WithTokenAuth("admin", "root"))
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetRepositories(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if *logins != 1 {
+		t.Fatalf("Expected a single login. Got: %d", *logins)
+	}
+}
+
+func TestTokenAuthRefreshesExpiredToken(t *testing.T) {
+	server, logins := newTokenTestServer(t)
+	client := newTestClient(t, server.URL, WithTokenAuth("admin", "root"))
+
+	ctx := context.Background()
+	if _, err := client.GetRepositories(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Simulate the server expiring the token
+	*logins++
+
+	err := client.CreateUser(ctx, User{Username: "test", Password: "test"})
+	if err != nil {
+		t.Fatalf("Expected request to be replayed with a fresh token. Got: %s", err)
+	}
+	// One login for the initial token, one simulated expiry and one refresh
+	if *logins != 3 {
+		t.Fatalf("Expected the client to log in again. Got counter: %d", *logins)
+	}
+}
+
+func TestTokenAuthInvalidCredentials(t *testing.T) {
+	server, _ := newTokenTestServer(t)
+	client := newTestClient(t, server.URL, WithTokenAuth("admin", "wrong"))
+
+	if _, err := client.GetRepositories(context.Background()); err == nil {
+		t.Fatal("Expected login with invalid credentials to fail")
+	}
+}
+
+func TestBearerTokenAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer static-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server.URL, WithBearerToken("static-token"))
+
+	if _, err := client.GetRepositories(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	issued := 0
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		id, secret, _ := r.BasicAuth()
+		if r.Form.Get("grant_type") != "client_credentials" || id != "ci" || secret != "secret" || r.Form.Get("scope") != "graphdb" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		issued++
+		w.Header().Set("Content-Type", "application/json")
+		// Tokens expiring within the refresh window are renewed on every request
+		_, _ = fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "bearer", "expires_in": 5}`, issued)
+	}))
+	defer idp.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", issued) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, WithOAuth2(clientcredentials.Config{
+		TokenURL:     idp.URL,
+		ClientID:     "ci",
+		ClientSecret: "secret",
+		Scopes:       []string{"graphdb"},
+	}))
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetRepositories(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if issued != 2 {
+		t.Fatalf("Expected the token to be refreshed before expiry. Got %d tokens", issued)
+	}
+}
+
+func TestOAuth2TokenEndpointTLS(t *testing.T) {
+	// The token endpoint is served with the same certificate as GraphDB, which is only trusted through the custom CA
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token": "tls-token", "token_type": "bearer", "expires_in": 3600}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer tls-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	client := newTestClient(t, server.URL, WithTLS(TLSConfig{CACertificate: string(ca)}), WithOAuth2(clientcredentials.Config{
+		TokenURL:     server.URL + "/token",
+		ClientID:     "ci",
+		ClientSecret: "secret",
+	}))
+
+	if _, err := client.GetRepositories(context.Background()); err != nil {
+		t.Fatalf("Expected the token to be requested with the custom CA. Got: %s", err)
+	}
+}
+
+func TestOAuth2TokenRetry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			// The token endpoint is rate limited at first
+			if requests++; requests == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token": "token", "token_type": "bearer", "expires_in": 3600}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, WithRetry(3, time.Millisecond), WithOAuth2(clientcredentials.Config{
+		TokenURL:     server.URL + "/token",
+		ClientID:     "ci",
+		ClientSecret: "secret",
+	}))
+
+	if _, err := client.GetRepositories(context.Background()); err != nil {
+		t.Fatalf("Expected the token request to be retried. Got: %s", err)
+	}
+	if requests != 2 {
+		t.Fatalf("Expected 2 token requests. Got: %d", requests)
+	}
+}
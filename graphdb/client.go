@@ -0,0 +1,249 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// API is the complete GraphDB management API implemented by Client.
+type API interface {
+	RepositoriesAPI
+	SecurityAPI
+	ImportAPI
+	ClusterAPI
+	InfoAPI
+}
+
+var _ API = &Client{}
+
+// Client is a client for the GraphDB REST API.
+// It is safe for concurrent use.
+type Client struct {
+	client    *http.Client
+	tlsConfig *tls.Config
+	baseURL   *url.URL
+	auth      authenticator
+	retry     retryPolicy
+	capture   *harCapture
+
+	compressUploads bool
+	requests        semaphore
+	cluster         *clusterRouter
+	repositoryLocks keyedMutex
+
+	mu   sync.RWMutex
+	info *ServerInfo
+}
+
+// NewClient creates a client for the GraphDB instance at endpoint, which may include a path prefix
+// (e.g. https://kg.example.com/graphdb/).
+// By default, requests are sent without credentials and transient failures are retried.
+// Every request is logged to the http tflog subsystem, with credentials redacted.
+func NewClient(endpoint string, opts ...Option) (*Client, error) {
+	baseURL, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid GraphDB endpoint %s: %w", endpoint, err)
+	}
+
+	c := &Client{
+		baseURL: baseURL,
+		auth:    &basicAuthenticator{},
+		retry:   newRetryPolicy(DefaultMaxRetries, DefaultRetryMaxWait),
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.client == nil {
+		transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+		if t, ok := http.DefaultTransport.(*http.Transport); ok {
+			transport = t.Clone()
+		}
+		transport.TLSClientConfig = c.tlsConfig
+		c.client = &http.Client{Transport: transport}
+	}
+
+	// Wrap a copy of the HTTP client, so that one supplied by the caller is left untouched
+	logged := *c.client
+	next := logged.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	logged.Transport = &loggingTransport{next: next, capture: c.capture}
+	c.client = &logged
+	return c, nil
+}
+
+// newRequest creates a request for the given REST resource, encoding body as JSON unless it is already a reader.
+func (c *Client) newRequest(ctx context.Context, method string, resource string, body any) (*http.Request, error) {
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	default:
+		encoded, err := json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	return http.NewRequestWithContext(ctx, method, c.createUrl(resource), reader)
+}
+
+// do sends the request and decodes the JSON response into out, if non-nil, or reads it as text if out is a *string.
+// It returns an APIError if the response status is not one of the expected ones.
+func (c *Client) do(req *http.Request, out any, expected ...int) error {
+	resp, err := c.doRequest(req)
+	if err != nil {
+		return err
+	}
+	if err := checkResponse(resp, expected...); err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		return nil
+	}
+	if text, ok := out.(*string); ok {
+		body, err := io.ReadAll(resp.Body)
+		*text = string(body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// doRequest sends the request, retrying transient failures according to the client's retry policy.
+func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	failovers := 0
+	for attempt := 0; ; attempt++ {
+		target := req
+		if c.cluster != nil {
+			target = c.cluster.route(c, req)
+		}
+
+		if err := c.requests.acquire(req.Context()); err != nil {
+			closeBody(req)
+			return nil, err
+		}
+		resp, err := c.send(target)
+		c.requests.release()
+
+		// Trying another node of a cluster doesn't count as a retry, as long as there are nodes left to try
+		if c.cluster != nil && failovers < len(c.cluster.nodes) && canRewind(req) && c.cluster.failover(c, target, resp, err) {
+			failovers++
+			attempt--
+			if resp != nil {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+			if req, err = rewindRequest(req); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if !c.retry.shouldRetry(req, resp, err, attempt) {
+			return resp, err
+		}
+
+		wait := c.retry.backoff(resp, attempt)
+		fields := map[string]any{"method": req.Method, "url": target.URL.String(), "attempt": attempt + 1, "wait": wait.String()}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		tflog.Debug(req.Context(), "Retrying GraphDB request", fields)
+
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+		req, err = rewindRequest(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send issues a single attempt of the request, refreshing the credentials if the server rejects them.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if err := c.auth.authenticate(req.Context(), c, req); err != nil {
+		closeBody(req)
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized || !c.auth.invalidate(req) {
+		return resp, nil
+	}
+
+	// The credentials have expired, so acquire new ones and replay the request, if we can
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+	retry, err := rewindRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.auth.authenticate(retry.Context(), c, retry); err != nil {
+		closeBody(retry)
+		return nil, err
+	}
+	return c.client.Do(retry)
+}
+
+// closeBody closes the body of a request which will not be sent, so that a streamed upload stops writing it.
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+func (c *Client) createUrl(resource string) string {
+	return c.baseURL.JoinPath("rest", resource).String()
+}
+
+// ParseEndpoint validates that the given value is an absolute http(s) URL suitable for use as a base URL.
+func ParseEndpoint(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("Unsupported scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.New("Missing host")
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, errors.New("Query parameters and fragments are not supported")
+	}
+	return u, nil
+}
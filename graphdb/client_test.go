@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestClient(t *testing.T, endpoint string, opts ...Option) *Client {
+	t.Helper()
+	client, err := NewClient(endpoint, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestClientEndpointPathPrefix(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.Method {
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusOK)
+		default:
+			_, _ = w.Write([]byte(`{"id": "repo"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL+"/graphdb/")
+
+	ctx := context.Background()
+	if _, err := client.GetRepository(ctx, "repo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteRepository(ctx, "repo"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"/graphdb/rest/repositories/repo", "/graphdb/rest/repositories/repo"}
+	for i, p := range want {
+		if paths[i] != p {
+			t.Fatalf("Unexpected request path. Wanted: %s. Got: %s", p, paths[i])
+		}
+	}
+}
+
+func TestParseEndpoint(t *testing.T) {
+	valid := []string{
+		"http://localhost:7200",
+		"https://kg.example.com/graphdb/",
+	}
+	for _, e := range valid {
+		if _, err := ParseEndpoint(e); err != nil {
+			t.Fatalf("Expected %s to be valid. Got: %s", e, err)
+		}
+	}
+
+	invalid := []string{
+		"localhost:7200",
+		"ftp://localhost",
+		"https:///graphdb",
+		"https://kg.example.com/graphdb?x=1",
+	}
+	for _, e := range invalid {
+		if _, err := ParseEndpoint(e); err == nil {
+			t.Fatalf("Expected %s to be invalid", e)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Cluster node states reported by GraphDB.
+const (
+	NodeStateLeader    = "LEADER"
+	NodeStateFollower  = "FOLLOWER"
+	NodeStateCandidate = "CANDIDATE"
+)
+
+// ClusterAPI inspects a GraphDB cluster.
+// All methods return an error satisfying IsNotFound when the instance is not part of a cluster.
+type ClusterAPI interface {
+	// GetClusterConfig returns the cluster configuration.
+	GetClusterConfig(ctx context.Context) (ClusterConfig, error)
+	// GetClusterGroupStatus returns the status of every node in the cluster.
+	GetClusterGroupStatus(ctx context.Context) ([]ClusterNodeStatus, error)
+	// GetClusterNodeStatus returns the status of the node the client is connected to.
+	GetClusterNodeStatus(ctx context.Context) (ClusterNodeStatus, error)
+}
+
+func (c *Client) GetClusterConfig(ctx context.Context) (ClusterConfig, error) {
+	var config ClusterConfig
+
+	req, err := c.newRequest(ctx, http.MethodGet, "cluster/config", nil)
+	if err != nil {
+		return config, err
+	}
+	if err := c.do(req, &config, http.StatusOK); err != nil {
+		return config, fmt.Errorf("Failed to get cluster config: %w", err)
+	}
+	return config, nil
+}
+
+func (c *Client) GetClusterGroupStatus(ctx context.Context) ([]ClusterNodeStatus, error) {
+	var nodes []ClusterNodeStatus
+
+	req, err := c.newRequest(ctx, http.MethodGet, "cluster/group/status", nil)
+	if err != nil {
+		return nodes, err
+	}
+	if err := c.do(req, &nodes, http.StatusOK); err != nil {
+		return nodes, fmt.Errorf("Failed to get cluster status: %w", err)
+	}
+	return nodes, nil
+}
+
+func (c *Client) GetClusterNodeStatus(ctx context.Context) (ClusterNodeStatus, error) {
+	var node ClusterNodeStatus
+
+	req, err := c.newRequest(ctx, http.MethodGet, "cluster/node/status", nil)
+	if err != nil {
+		return node, err
+	}
+	if err := c.do(req, &node, http.StatusOK); err != nil {
+		return node, fmt.Errorf("Failed to get cluster node status: %w", err)
+	}
+	return node, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, WithMaxConcurrentRequests(2))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetRepositories(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != 2 {
+		t.Fatalf("Expected at most 2 requests in flight. Got: %d", maxInFlight)
+	}
+}
+
+func TestMaxConcurrentRequestsCancelled(t *testing.T) {
+	client := newTestClient(t, "http://localhost:7200", WithMaxConcurrentRequests(1))
+	// Occupy the only slot
+	if err := client.requests.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetRepositories(ctx); err == nil {
+		t.Fatal("Expected request waiting for a slot to be cancelled")
+	}
+}
+
+func TestLockRepository(t *testing.T) {
+	client := newTestClient(t, "http://localhost:7200")
+	ctx := context.Background()
+
+	unlock, err := client.LockRepository(ctx, "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Other repositories are not affected
+	other, err := client.LockRepository(ctx, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other()
+
+	// The same repository is locked until released
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.LockRepository(timeout, "repo"); err == nil {
+		t.Fatal("Expected locked repository to time out")
+	}
+
+	acquired := make(chan func())
+	go func() {
+		next, err := client.LockRepository(ctx, "repo")
+		if err != nil {
+			t.Error(err)
+		}
+		acquired <- next
+	}()
+	unlock()
+	// Unlocking twice has no effect
+	unlock()
+	(<-acquired)()
+
+	if len(client.repositoryLocks.locks) != 0 {
+		t.Fatalf("Expected released locks to be discarded. Got: %v", client.repositoryLocks.locks)
+	}
+}
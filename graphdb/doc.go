@@ -0,0 +1,17 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package graphdb is a client for the Ontotext GraphDB management REST API.
+//
+// The API is split into one interface per area (RepositoriesAPI, SecurityAPI, ImportAPI and ClusterAPI),
+// all of which are implemented by Client, so that consumers can depend on, and mock, only what they use.
+//
+//	client, err := graphdb.NewClient("https://kg.example.com/graphdb/",
+//		graphdb.WithTokenAuth("admin", "root"),
+//		graphdb.WithRetry(5, time.Minute),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	repositories, err := client.GetRepositories(ctx)
+package graphdb
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseErrorMessage(t *testing.T) {
+	tests := map[string]string{
+		`{"message": "Repository TestRepo does not exist"}`: "Repository TestRepo does not exist",
+		`{"error": "Conflict"}`:                             "Conflict",
+		"User already exists\n":                             "User already exists",
+		"":                                                  "",
+	}
+	for body, want := range tests {
+		if got := parseErrorMessage([]byte(body)); got != want {
+			t.Fatalf("Failed to parse error message from %q. Wanted: %s. Got: %s", body, want, got)
+		}
+	}
+}
+
+func TestAPIErrorNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Repository missing does not exist"}`))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server.URL)
+
+	_, err := client.GetRepository(context.Background(), "missing")
+	if !IsNotFound(err) {
+		t.Fatalf("Expected a not found error. Got: %v", err)
+	}
+	if IsConflict(err) {
+		t.Fatal("Not found error should not be a conflict")
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatal("Expected an APIError")
+	}
+	if apiErr.Method != http.MethodGet || apiErr.URL != server.URL+"/rest/repositories/missing" || apiErr.Message != "Repository missing does not exist" {
+		t.Fatalf("Unexpected error details: %+v", apiErr)
+	}
+}
+
+func TestAPIErrorConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_, _ = w.Write([]byte("User TestUser already exists"))
+	}))
+	defer server.Close()
+	client := newTestClient(t, server.URL)
+
+	err := client.CreateUser(context.Background(), User{Username: "TestUser"})
+	if !IsConflict(err) {
+		t.Fatalf("Expected a conflict error. Got: %v", err)
+	}
+}
+
+func TestConnectionErrorClassification(t *testing.T) {
+	ctx := context.Background()
+
+	tlsServer, _ := newTLSTestServer(t)
+	_, err := tlsTestClient(t, tlsServer, TLSConfig{}).GetRepositories(ctx)
+	if !IsTLSError(err) || IsUnreachable(err) {
+		t.Fatalf("Expected an untrusted certificate to be a TLS error. Got: %v", err)
+	}
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	_, err = newTestClient(t, closed.URL, WithRetry(0, time.Second)).GetRepositories(ctx)
+	if !IsUnreachable(err) || IsTLSError(err) {
+		t.Fatalf("Expected a closed port to be unreachable. Got: %v", err)
+	}
+
+	server, _ := newTokenTestServer(t)
+	_, err = newTestClient(t, server.URL, WithTokenAuth("admin", "wrong")).GetRepositories(ctx)
+	if !IsUnauthorized(err) {
+		t.Fatalf("Expected a failed login to be unauthorized. Got: %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// clusterRouter spreads requests across the nodes of a GraphDB cluster.
+// Mutating requests are sent to the leader, which is discovered from the cluster status and cached until a node
+// turns out to be unreachable or answers that it is not the leader. Read requests go to the first node which is
+// reachable, failing over to the next one when it stops responding.
+type clusterRouter struct {
+	nodes []*url.URL
+
+	mu sync.Mutex
+	// current is the index of the node used for reads, and for writes when the leader is unknown
+	current int
+	leader  *url.URL
+	// standalone is set when the nodes turn out not to be part of a cluster, so the leader is not looked up again
+	standalone bool
+	// lookup is closed when the leader lookup in progress, if any, completes
+	lookup chan struct{}
+}
+
+func newClusterRouter(nodes []*url.URL) *clusterRouter {
+	return &clusterRouter{nodes: nodes}
+}
+
+// route returns a copy of req, which must target the client's base URL, sent to the node which should handle it.
+func (r *clusterRouter) route(c *Client, req *http.Request) *http.Request {
+	r.mu.Lock()
+	node, leader, known := r.nodes[r.current], r.leader, r.leader != nil || r.standalone
+	r.mu.Unlock()
+
+	if isMutating(req.Method) {
+		if !known {
+			leader = r.findLeader(req.Context(), c)
+		}
+		if leader != nil {
+			node = leader
+		}
+	}
+	return rebase(req, c.baseURL, node)
+}
+
+// failover reports whether the request should be sent again to another node, after the node it was sent to
+// either could not be reached, or rejected a mutating request because it is no longer the leader.
+func (r *clusterRouter) failover(c *Client, req *http.Request, resp *http.Response, err error) bool {
+	r.mu.Lock()
+	if err != nil {
+		defer r.mu.Unlock()
+		if !IsUnreachable(err) {
+			return false
+		}
+		if r.leader != nil && sameNode(r.leader, req.URL) {
+			r.leader = nil
+		}
+		if sameNode(r.nodes[r.current], req.URL) {
+			r.current = (r.current + 1) % len(r.nodes)
+		}
+		tflog.Warn(req.Context(), "GraphDB node unreachable, failing over", map[string]any{"node": req.URL.Host, "error": err.Error()})
+		return true
+	}
+	standalone := r.standalone
+	r.mu.Unlock()
+
+	if !isMutating(req.Method) || !isNotLeaderResponse(resp) || standalone {
+		return false
+	}
+	tflog.Debug(req.Context(), "GraphDB node rejected write, looking up the leader", map[string]any{"node": req.URL.Host, "status": resp.StatusCode})
+	leader := r.findLeader(req.Context(), c)
+	return leader != nil && !sameNode(leader, req.URL)
+}
+
+// findLeader looks up the leader and caches it. The lookup runs without holding the mutex, so that requests which
+// do not need the leader are not held up by unreachable nodes, and concurrent callers share a single lookup.
+func (r *clusterRouter) findLeader(ctx context.Context, c *Client) *url.URL {
+	r.mu.Lock()
+	if lookup := r.lookup; lookup != nil {
+		r.mu.Unlock()
+		select {
+		case <-lookup:
+		case <-ctx.Done():
+			return nil
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.leader
+	}
+	lookup := make(chan struct{})
+	r.lookup = lookup
+	start := r.current
+	r.mu.Unlock()
+
+	leader, standalone := r.discoverLeader(ctx, c, start)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.leader = leader
+	r.standalone = r.standalone || standalone
+	r.lookup = nil
+	close(lookup)
+	return leader
+}
+
+// discoverLeader asks each node in turn, starting with the one at index start, for the cluster status.
+// It returns the leader or nil if it cannot be found, and whether the nodes turned out not to be a cluster.
+func (r *clusterRouter) discoverLeader(ctx context.Context, c *Client, start int) (*url.URL, bool) {
+	for i := range r.nodes {
+		node := r.nodes[(start+i)%len(r.nodes)]
+		statuses, err := r.clusterStatus(ctx, c, node)
+		if IsNotFound(err) {
+			tflog.Debug(ctx, "GraphDB is not a cluster, sending all requests to the same node", map[string]any{"node": node.Host})
+			return nil, true
+		}
+		if err != nil {
+			tflog.Debug(ctx, "Failed to get cluster status", map[string]any{"node": node.Host, "error": err.Error()})
+			continue
+		}
+
+		for _, status := range statuses {
+			if status.NodeState == NodeStateLeader {
+				leader := r.matchNode(status.Endpoint)
+				if leader != nil {
+					tflog.Debug(ctx, "Discovered GraphDB cluster leader", map[string]any{"leader": leader.Host})
+				}
+				return leader, false
+			}
+		}
+	}
+	return nil, false
+}
+
+func (r *clusterRouter) clusterStatus(ctx context.Context, c *Client, node *url.URL) ([]ClusterNodeStatus, error) {
+	var statuses []ClusterNodeStatus
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, node.JoinPath("rest", "cluster", "group", "status").String(), nil)
+	if err != nil {
+		return statuses, err
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return statuses, err
+	}
+	if err := checkResponse(resp, http.StatusOK); err != nil {
+		return statuses, err
+	}
+	defer resp.Body.Close()
+	err = json.NewDecoder(resp.Body).Decode(&statuses)
+	return statuses, err
+}
+
+// matchNode returns the configured node with the same address as the endpoint advertised in the cluster status.
+// If there is none, e.g. because the nodes are configured by IP address, the advertised endpoint is used as-is.
+func (r *clusterRouter) matchNode(endpoint string) *url.URL {
+	advertised, err := ParseEndpoint(endpoint)
+	if err != nil {
+		return nil
+	}
+	for _, node := range r.nodes {
+		if sameNode(node, advertised) {
+			return node
+		}
+	}
+	return advertised
+}
+
+// nodeFor returns the base URL of the node the given request URL is addressed to.
+func (c *Client) nodeFor(u *url.URL) *url.URL {
+	if c.cluster == nil || sameNode(c.baseURL, u) {
+		return c.baseURL
+	}
+	// The nodes never change, so they can be read without holding the lock
+	for _, node := range c.cluster.nodes {
+		if sameNode(node, u) {
+			return node
+		}
+	}
+	// An advertised leader which is not one of the configured nodes
+	return &url.URL{Scheme: u.Scheme, Host: u.Host, Path: c.baseURL.Path}
+}
+
+// rebase returns a copy of req sent to the node at to, rather than the one at from.
+// The request body is shared with req.
+func rebase(req *http.Request, from *url.URL, to *url.URL) *http.Request {
+	if sameNode(from, to) && from.Path == to.Path {
+		return req
+	}
+	rebased := req.Clone(req.Context())
+	u := *req.URL
+	u.Scheme = to.Scheme
+	u.Host = to.Host
+	u.Path = strings.TrimSuffix(to.Path, "/") + strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(from.Path, "/"))
+	u.RawPath = ""
+	rebased.URL = &u
+	rebased.Host = ""
+	return rebased
+}
+
+func sameNode(a *url.URL, b *url.URL) bool {
+	return a.Scheme == b.Scheme && strings.EqualFold(a.Host, b.Host)
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// isNotLeaderResponse reports whether a node rejected a write because it is not the leader.
+// Followers answer 421 Misdirected Request, while a node which has just lost the leadership answers
+// 503 Service Unavailable until the election completes.
+func isNotLeaderResponse(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusMisdirectedRequest || resp.StatusCode == http.StatusServiceUnavailable
+}
+
+// canRewind reports whether the request body can be sent again.
+func canRewind(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
@@ -0,0 +1,240 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testCluster is a set of fake GraphDB nodes, which only accept writes on the leader.
+type testCluster struct {
+	t     *testing.T
+	nodes []*httptest.Server
+
+	// statusGate, if set, holds up cluster status lookups until it is closed
+	statusGate chan struct{}
+
+	mu       sync.Mutex
+	leader   int
+	requests []string
+	lookups  int
+}
+
+func newTestCluster(t *testing.T, size int) *testCluster {
+	c := &testCluster{t: t}
+	for i := 0; i < size; i++ {
+		i := i
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			c.handle(i, w, r)
+		}))
+		t.Cleanup(server.Close)
+		c.nodes = append(c.nodes, server)
+	}
+	return c
+}
+
+func (c *testCluster) handle(node int, w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/rest/cluster/group/status" {
+		c.mu.Lock()
+		c.lookups++
+		c.mu.Unlock()
+		if c.statusGate != nil {
+			<-c.statusGate
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if r.URL.Path == "/rest/login" {
+		// The nodes share their token secret
+		w.Header().Set("Authorization", "GDB token")
+		return
+	}
+	if r.URL.Path == "/rest/cluster/group/status" {
+		if c.leader < 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		statuses := []ClusterNodeStatus{}
+		for i, n := range c.nodes {
+			state := NodeStateFollower
+			if i == c.leader {
+				state = NodeStateLeader
+			}
+			statuses = append(statuses, ClusterNodeStatus{Endpoint: n.URL, NodeState: state})
+		}
+		_ = json.NewEncoder(w).Encode(statuses)
+		return
+	}
+
+	c.requests = append(c.requests, r.Method+" "+c.nodes[node].URL)
+	switch {
+	case r.Method == http.MethodGet:
+		_, _ = w.Write([]byte(`[]`))
+	case c.leader >= 0 && node != c.leader:
+		w.WriteHeader(http.StatusMisdirectedRequest)
+	default:
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func (c *testCluster) client(opts ...Option) *Client {
+	endpoints := []string{}
+	for _, n := range c.nodes[1:] {
+		endpoints = append(endpoints, n.URL)
+	}
+	opts = append(opts, WithClusterEndpoints(endpoints...), WithRetry(0, time.Millisecond))
+	return newTestClient(c.t, c.nodes[0].URL, opts...)
+}
+
+func (c *testCluster) setLeader(leader int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.leader = leader
+}
+
+// statusLookups returns the number of cluster status requests received by any node.
+func (c *testCluster) statusLookups() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lookups
+}
+
+// lastRequest returns the method and node of the most recent request, other than cluster status lookups.
+func (c *testCluster) lastRequest() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests[len(c.requests)-1]
+}
+
+func TestClusterRoutesWritesToLeader(t *testing.T) {
+	cluster := newTestCluster(t, 3)
+	cluster.setLeader(1)
+	client := cluster.client()
+	ctx := context.Background()
+
+	if err := client.CreateUser(ctx, User{Username: "TestUser"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "POST " + cluster.nodes[1].URL; cluster.lastRequest() != want {
+		t.Fatalf("Expected write to go to the leader. Wanted: %s. Got: %s", want, cluster.lastRequest())
+	}
+
+	if _, err := client.GetRepositories(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if want := "GET " + cluster.nodes[0].URL; cluster.lastRequest() != want {
+		t.Fatalf("Expected read to go to the first node. Wanted: %s. Got: %s", want, cluster.lastRequest())
+	}
+}
+
+func TestClusterLeaderChange(t *testing.T) {
+	cluster := newTestCluster(t, 3)
+	cluster.setLeader(1)
+	client := cluster.client()
+	ctx := context.Background()
+
+	if err := client.CreateUser(ctx, User{Username: "TestUser"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The former leader rejects the write, so the new leader is looked up
+	cluster.setLeader(2)
+	if err := client.CreateUser(ctx, User{Username: "OtherUser"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "POST " + cluster.nodes[2].URL; cluster.lastRequest() != want {
+		t.Fatalf("Expected write to go to the new leader. Wanted: %s. Got: %s", want, cluster.lastRequest())
+	}
+}
+
+func TestClusterFailover(t *testing.T) {
+	cluster := newTestCluster(t, 3)
+	cluster.setLeader(0)
+	client := cluster.client(WithTokenAuth("admin", "root"))
+	ctx := context.Background()
+
+	// The first node, which is also the leader, goes down and the second one is elected
+	cluster.nodes[0].Close()
+	cluster.setLeader(1)
+
+	if _, err := client.GetRepositories(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if want := "GET " + cluster.nodes[1].URL; cluster.lastRequest() != want {
+		t.Fatalf("Expected read to fail over to the next node. Wanted: %s. Got: %s", want, cluster.lastRequest())
+	}
+	if err := client.CreateUser(ctx, User{Username: "TestUser"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "POST " + cluster.nodes[1].URL; cluster.lastRequest() != want {
+		t.Fatalf("Expected write to go to the new leader. Wanted: %s. Got: %s", want, cluster.lastRequest())
+	}
+}
+
+func TestClusterStandalone(t *testing.T) {
+	cluster := newTestCluster(t, 2)
+	cluster.setLeader(-1)
+	client := cluster.client()
+
+	if err := client.CreateUser(context.Background(), User{Username: "TestUser"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "POST " + cluster.nodes[0].URL; cluster.lastRequest() != want {
+		t.Fatalf("Expected write to go to the first node. Wanted: %s. Got: %s", want, cluster.lastRequest())
+	}
+}
+
+func TestClusterLeaderLookupDoesNotBlock(t *testing.T) {
+	cluster := newTestCluster(t, 2)
+	cluster.setLeader(1)
+	release := make(chan struct{})
+	cluster.statusGate = release
+	client := cluster.client()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- client.CreateUser(ctx, User{Username: fmt.Sprintf("TestUser%d", i)})
+		}(i)
+	}
+	for cluster.statusLookups() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Reads do not wait for the leader lookup in progress
+	readCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if _, err := client.GetRepositories(readCtx); err != nil {
+		t.Fatalf("Expected read to succeed while the leader is looked up. Got: %v", err)
+	}
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The concurrent writes share a single lookup
+	if lookups := cluster.statusLookups(); lookups != 1 {
+		t.Fatalf("Expected the leader to be looked up once. Got: %d lookups", lookups)
+	}
+	if want := "POST " + cluster.nodes[1].URL; cluster.lastRequest() != want {
+		t.Fatalf("Expected writes to go to the leader. Wanted: %s. Got: %s", want, cluster.lastRequest())
+	}
+}
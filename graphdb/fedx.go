@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/nickrobison/terraform-provider-graphdb/internal/turtle"
+)
+
+// NamespaceFedX is the namespace of the FedX configuration vocabulary.
+const NamespaceFedX = "http://www.fluidops.com/config/fedx#"
+
+// FedXMembersParam is the parameter of a FedX repository which lists its members.
+const FedXMembersParam = "member"
+
+// Stores of the members of a FedX federation.
+const (
+	// FedXStoreLocal is a repository of the GraphDB instance hosting the federation.
+	FedXStoreLocal = "ResolvableRepository"
+	// FedXStoreRemote is a repository of another GraphDB or RDF4J server.
+	FedXStoreRemote = "RemoteRepository"
+	// FedXStoreSPARQL is a SPARQL endpoint.
+	FedXStoreSPARQL = "SPARQLEndpoint"
+)
+
+// FedXMember is a member of a FedX federation. The fields which apply depend on the store, and flags which are not
+// set use the FedX defaults.
+type FedXMember struct {
+	Store              string `json:"store"`
+	RepositoryName     string `json:"repositoryName,omitempty"`
+	RepositoryServer   string `json:"repositoryServer,omitempty"`
+	Endpoint           string `json:"endpoint,omitempty"`
+	Username           string `json:"username,omitempty"`
+	Password           string `json:"password,omitempty"`
+	RespectRights      *bool  `json:"respectRights,omitempty,string"`
+	Writable           *bool  `json:"writable,omitempty,string"`
+	SupportsASKQueries *bool  `json:"supportsASKQueries,omitempty,string"`
+}
+
+// NewFedXMembersParam returns the parameter listing the members of a FedX repository, to update them in place.
+func NewFedXMembersParam(members []FedXMember) (RepositoryParam, error) {
+	if members == nil {
+		members = []FedXMember{}
+	}
+	raw, err := json.Marshal(members)
+	if err != nil {
+		return RepositoryParam{}, fmt.Errorf("Failed to encode FedX members: %w", err)
+	}
+	return RepositoryParam{Name: FedXMembersParam, Label: "FedX members", Raw: raw}, nil
+}
+
+// FormatFedXConfig renders the configuration of a graphdb:FedXRepository in Turtle syntax, with the ID, title and
+// parameters of repo, such as the join settings, and the given members. The members parameter of repo is ignored.
+func FormatFedXConfig(repo Repository, members []FedXMember) string {
+	var b strings.Builder
+	b.WriteString("@prefix rdfs: <" + NamespaceRDFS + "> .\n")
+	b.WriteString("@prefix rep: <" + NamespaceRep + "> .\n")
+	b.WriteString("@prefix fedx: <" + NamespaceFedX + "> .\n\n")
+
+	b.WriteString("[] a rep:Repository ;\n")
+	fmt.Fprintf(&b, "    rep:repositoryID %s ;\n", turtle.NewLiteral(repo.ID))
+	fmt.Fprintf(&b, "    rdfs:label %s ;\n", turtle.NewLiteral(repo.Title))
+	b.WriteString("    rep:repositoryImpl [\n")
+	b.WriteString("        rep:repositoryType \"graphdb:FedXRepository\"")
+
+	names := make([]string, 0, len(repo.Params))
+	for name := range repo.Params {
+		if name != FedXMembersParam {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&b, " ;\n        fedx:%s %s", name, paramLiteral(repo.Params[name].Value))
+	}
+
+	for i, m := range members {
+		if i == 0 {
+			b.WriteString(" ;\n        fedx:member [\n")
+		} else {
+			b.WriteString(", [\n")
+		}
+		fmt.Fprintf(&b, "            fedx:store %s", turtle.NewLiteral(m.Store))
+		fields := []struct {
+			name  string
+			value string
+		}{
+			{"repositoryServer", m.RepositoryServer},
+			{"repositoryName", m.RepositoryName},
+			{"endpoint", m.Endpoint},
+			{"username", m.Username},
+			{"password", m.Password},
+			{"respectRights", formatFlag(m.RespectRights)},
+			{"writable", formatFlag(m.Writable)},
+			{"supportsASKQueries", formatFlag(m.SupportsASKQueries)},
+		}
+		for _, f := range fields {
+			if f.value != "" {
+				fmt.Fprintf(&b, " ;\n            fedx:%s %s", f.name, turtle.NewLiteral(f.value))
+			}
+		}
+		b.WriteString("\n        ]")
+	}
+	b.WriteString("\n    ] .\n")
+	return b.String()
+}
+
+// paramLiteral writes integers and booleans as such, and other values as strings.
+func paramLiteral(value string) string {
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return value
+	}
+	if value == "true" || value == "false" {
+		return value
+	}
+	return turtle.NewLiteral(value).String()
+}
+
+func formatFlag(flag *bool) string {
+	if flag == nil {
+		return ""
+	}
+	return strconv.FormatBool(*flag)
+}
+
+// ParseFedXMembers extracts the members of a FedX repository from its configuration in Turtle syntax, in the order
+// they appear in the configuration.
+func ParseFedXMembers(config string) ([]FedXMember, error) {
+	g, err := turtle.Parse(config)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid repository configuration: %w", err)
+	}
+	node, ok := repositoryNode(g)
+	if !ok {
+		return nil, errors.New("Invalid repository configuration: no rep:repositoryID found")
+	}
+	impl, ok := g.Object(node, NamespaceRep+"repositoryImpl")
+	if !ok {
+		return nil, nil
+	}
+
+	var members []FedXMember
+	for _, m := range g.Objects(impl, NamespaceFedX+"member") {
+		value := func(name string) string {
+			v, _ := g.Object(m, NamespaceFedX+name)
+			return v.Value
+		}
+		flag := func(name string) *bool {
+			v, ok := g.Object(m, NamespaceFedX+name)
+			if !ok {
+				return nil
+			}
+			b, err := strconv.ParseBool(v.Value)
+			if err != nil {
+				return nil
+			}
+			return &b
+		}
+		members = append(members, FedXMember{
+			Store:              value("store"),
+			RepositoryName:     value("repositoryName"),
+			RepositoryServer:   value("repositoryServer"),
+			Endpoint:           value("endpoint"),
+			Username:           value("username"),
+			Password:           value("password"),
+			RespectRights:      flag("respectRights"),
+			Writable:           flag("writable"),
+			SupportsASKQueries: flag("supportsASKQueries"),
+		})
+	}
+	return members, nil
+}
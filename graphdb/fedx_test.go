@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func testFedXMembers() []FedXMember {
+	yes, no := true, false
+	return []FedXMember{
+		{Store: FedXStoreLocal, RepositoryName: "people", RespectRights: &yes, Writable: &yes},
+		{Store: FedXStoreRemote, RepositoryServer: "http://remote:7200", RepositoryName: "cities", Username: "reader", Password: `p"ss`},
+		{Store: FedXStoreSPARQL, Endpoint: "https://dbpedia.org/sparql", SupportsASKQueries: &no},
+	}
+}
+
+func TestFormatFedXConfig(t *testing.T) {
+	repo := Repository{
+		ID:    "Federation",
+		Title: "Federation",
+		Params: map[string]RepositoryParam{
+			"joinWorkerThreads":        {Name: "joinWorkerThreads", Value: "10"},
+			"includeInferredDefault":   {Name: "includeInferredDefault", Value: "false"},
+			"sourceSelectionCacheSpec": {Name: "sourceSelectionCacheSpec", Value: "maximumSize=1000"},
+			FedXMembersParam:           {Name: FedXMembersParam, Value: "ignored"},
+		},
+	}
+	config := FormatFedXConfig(repo, testFedXMembers())
+
+	members, err := ParseFedXMembers(config)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %s", config, err)
+	}
+	if !reflect.DeepEqual(members, testFedXMembers()) {
+		t.Fatalf("Unexpected members. Wanted: %+v. Got: %+v", testFedXMembers(), members)
+	}
+
+	parsed, err := ParseRepositoryConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Type != "fedx" || parsed.ID != "Federation" {
+		t.Fatalf("Unexpected repository: %+v", parsed)
+	}
+	want := map[string]string{"joinWorkerThreads": "10", "includeInferredDefault": "false", "sourceSelectionCacheSpec": "maximumSize=1000"}
+	for name, value := range want {
+		if parsed.Params[name].Value != value {
+			t.Fatalf("Unexpected value for %s. Wanted: %q. Got: %q", name, value, parsed.Params[name].Value)
+		}
+	}
+	if _, ok := parsed.Params[FedXMembersParam]; ok {
+		t.Fatal("Expected the members not to be a parameter of the configuration")
+	}
+}
+
+func TestFedXMembersParam(t *testing.T) {
+	param, err := NewFedXMembersParam(testFedXMembers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(Repository{ID: "Federation", Params: map[string]RepositoryParam{FedXMembersParam: param}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The members are sent as a list of objects, and kept as such when read back
+	var repo Repository
+	if err := json.Unmarshal(data, &repo); err != nil {
+		t.Fatal(err)
+	}
+	var members []FedXMember
+	if err := json.Unmarshal(repo.Params[FedXMembersParam].Raw, &members); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(members, testFedXMembers()) {
+		t.Fatalf("Unexpected members. Wanted: %+v. Got: %+v", testFedXMembers(), members)
+	}
+}
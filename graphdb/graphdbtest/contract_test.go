@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdbtest
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
+)
+
+// The contract tests run the same scenarios against the fake server and, when GRAPHDB_CONTRACT_ENDPOINT is set, against a
+// real GraphDB server, such as the one started by docker-compose.yml. They check that the fake behaves like the server
+// it stands in for, and use the admin credentials of a fresh installation.
+const envContractEndpoint = "GRAPHDB_CONTRACT_ENDPOINT"
+
+const (
+	contractRepositoryID = "ContractRepo"
+	contractUsername     = "contract-user"
+)
+
+// contractRepositoryConfig is a complete configuration, since GraphDB rejects repositories without an implementation.
+const contractRepositoryConfig = `
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+@prefix rep: <http://www.openrdf.org/config/repository#> .
+@prefix sr: <http://www.openrdf.org/config/repository/sail#> .
+@prefix sail: <http://www.openrdf.org/config/sail#> .
+@prefix graphdb: <http://www.ontotext.com/config/graphdb#> .
+
+[] a rep:Repository ;
+    rep:repositoryID "ContractRepo" ;
+    rdfs:label "Contract repository" ;
+    rep:repositoryImpl [
+        rep:repositoryType "graphdb:SailRepository" ;
+        sr:sailImpl [
+            sail:sailType "graphdb:Sail" ;
+            graphdb:ruleset "rdfsplus-optimized" ;
+            graphdb:query-timeout "30"
+        ]
+    ] .
+`
+
+// contractClients returns an admin client for each server the contract tests run against, keyed by a name for subtests.
+func contractClients(t *testing.T) map[string]*graphdb.Client {
+	t.Helper()
+	server := NewServer(WithSecurity())
+	t.Cleanup(server.Close)
+	clients := map[string]*graphdb.Client{
+		"fake": newClient(t, server, graphdb.WithBasicAuth(AdminUsername, AdminPassword)),
+	}
+
+	if endpoint := os.Getenv(envContractEndpoint); endpoint != "" {
+		client, err := graphdb.NewClient(endpoint, graphdb.WithBasicAuth(AdminUsername, AdminPassword))
+		if err != nil {
+			t.Fatal(err)
+		}
+		clients["graphdb"] = client
+	}
+	return clients
+}
+
+func TestContractRepositories(t *testing.T) {
+	for name, client := range contractClients(t) {
+		client := client
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if err := client.CreateRepository(ctx, strings.NewReader(contractRepositoryConfig)); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				_ = client.DeleteRepository(context.Background(), contractRepositoryID)
+			})
+			if err := client.CreateRepository(ctx, strings.NewReader(contractRepositoryConfig)); err == nil {
+				t.Fatal("Expected creating a duplicate repository to fail")
+			}
+
+			repo, err := client.GetRepository(ctx, contractRepositoryID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if repo.ID != contractRepositoryID || repo.Title != "Contract repository" || repo.Type != "graphdb" {
+				t.Fatalf("Unexpected repository: %+v", repo)
+			}
+
+			// Parameters are keyed by the camelCase form of their local names in the configuration
+			if param := repo.Params["queryTimeout"]; param.Name != "queryTimeout" || param.Value != "30" {
+				t.Fatalf("Expected the query timeout to be keyed in camelCase. Got: %+v", repo.Params)
+			}
+
+			repos, err := client.GetRepositories(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			found := false
+			for _, r := range repos {
+				found = found || r.Name == contractRepositoryID && r.Type == "graphdb"
+			}
+			if !found {
+				t.Fatalf("Expected %s to be listed. Got: %+v", contractRepositoryID, repos)
+			}
+
+			if err := client.DeleteRepository(ctx, contractRepositoryID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.GetRepository(ctx, contractRepositoryID); !graphdb.IsNotFound(err) {
+				t.Fatalf("Expected deleted repository to be not found. Got: %v", err)
+			}
+		})
+	}
+}
+
+func TestContractUsers(t *testing.T) {
+	for name, client := range contractClients(t) {
+		client := client
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			user := graphdb.User{Username: contractUsername, Password: "secret", Authorities: []string{"ROLE_USER"}}
+			if err := client.CreateUser(ctx, user); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				_ = client.DeleteUser(context.Background(), contractUsername)
+			})
+
+			user.Authorities = []string{"ROLE_REPO_MANAGER"}
+			if err := client.UpdateUser(ctx, contractUsername, user); err != nil {
+				t.Fatal(err)
+			}
+			got, err := client.GetUser(ctx, contractUsername)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Username != contractUsername || got.Password != "" || !contains(got.Authorities, "ROLE_REPO_MANAGER") {
+				t.Fatalf("Unexpected user: %+v", got)
+			}
+
+			if err := client.DeleteUser(ctx, contractUsername); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := client.GetUser(ctx, contractUsername); !graphdb.IsNotFound(err) {
+				t.Fatalf("Expected deleted user to be not found. Got: %v", err)
+			}
+		})
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,824 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package graphdbtest provides an in-process fake of the GraphDB REST API, for hermetic tests.
+//
+// The fake keeps all state in memory and implements the subset of the API used by the graphdb package. It mimics the
+// status codes and payloads of a real GraphDB instance where clients depend on them, e.g. repository parameters are
+// keyed in camelCase and parameters keyed otherwise are ignored, but it does not validate configurations like GraphDB.
+package graphdbtest
+
+import (
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
+)
+
+const (
+	// AdminUsername and AdminPassword are the credentials of the built-in admin account.
+	AdminUsername = "admin"
+	AdminPassword = "root"
+
+	// DefaultVersion and DefaultProductType are reported by the server unless WithVersion is used.
+	DefaultVersion     = "10.6.0"
+	DefaultProductType = "free"
+)
+
+// Server is a fake GraphDB server.
+type Server struct {
+	// URL is the base URL of the server, suitable for graphdb.NewClient
+	URL string
+
+	server      *httptest.Server
+	security    bool
+	version     string
+	productType string
+
+	mu           sync.Mutex
+	repositories map[string]*repository
+	locations    map[string]bool
+	files        map[string]string
+	users        map[string]graphdb.User
+	passwords    map[string]string
+	serverFiles  map[string][]graphdb.ImportResource
+	uploads      map[string][]graphdb.ImportResource
+	cluster      []graphdb.ClusterNodeStatus
+	startupPolls int
+}
+
+type repository struct {
+	info     graphdb.Repository
+	config   string
+	updates  int
+	state    string
+	restarts int
+	size     graphdb.RepositorySize
+	// starting is how many more times the repository is reported as starting
+	starting int
+}
+
+// Option configures the fake server.
+type Option func(s *Server)
+
+// WithSecurity enables security, so that every request must be authenticated,
+// either with basic authentication or with a token obtained from the login endpoint.
+func WithSecurity() Option {
+	return func(s *Server) {
+		s.security = true
+	}
+}
+
+// WithVersion sets the version and product type (free, se, ee or enterprise) reported by the server.
+// Versions before 10.0 serve users under the GraphDB 9.x path.
+func WithVersion(version string, productType string) Option {
+	return func(s *Server) {
+		s.version = version
+		s.productType = productType
+	}
+}
+
+// WithRepositoryStartup makes repositories report that they are starting for the first polls times their state is
+// listed after they are created or restarted, as GraphDB does while it initializes them.
+func WithRepositoryStartup(polls int) Option {
+	return func(s *Server) {
+		s.startupPolls = polls
+	}
+}
+
+// WithLocation attaches a remote location, so that repositories can be managed in it with the location query parameter.
+// Locations are matched regardless of a trailing slash.
+func WithLocation(location string) Option {
+	return func(s *Server) {
+		s.locations[strings.TrimSuffix(location, "/")] = true
+	}
+}
+
+// NewServer starts a fake GraphDB server, which must be closed by the caller.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		repositories: map[string]*repository{},
+		locations:    map[string]bool{},
+		files:        map[string]string{},
+		users: map[string]graphdb.User{
+			AdminUsername: {Username: AdminUsername, Authorities: []string{"ROLE_ADMIN"}},
+		},
+		passwords:   map[string]string{AdminUsername: AdminPassword},
+		serverFiles: map[string][]graphdb.ImportResource{},
+		uploads:     map[string][]graphdb.ImportResource{},
+		version:     DefaultVersion,
+		productType: DefaultProductType,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	usersPath := "/rest/security/users"
+	if v, err := graphdb.ParseVersion(s.version); err == nil && v.Major < 10 {
+		usersPath = "/rest/security/user"
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/login", s.handleLogin)
+	mux.HandleFunc("/rest/info/version", s.handleVersion)
+	mux.HandleFunc("/rest/security", s.handleSecurity)
+	mux.HandleFunc("/rest/security/authenticatedUser", s.authenticated(s.handleAuthenticatedUser))
+	mux.HandleFunc("/rest/repositories", s.authenticated(s.handleRepositories))
+	mux.HandleFunc("/rest/repositories/", s.authenticated(s.handleRepository))
+	mux.HandleFunc(usersPath, s.authenticated(s.handleUsers))
+	mux.HandleFunc(usersPath+"/", s.authenticated(s.handleUser))
+	mux.HandleFunc("/rest/cluster/", s.authenticated(s.handleCluster))
+
+	s.server = httptest.NewServer(decompress(mux))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Repository returns the repository with the given ID, if it exists.
+// The IDs of repositories in remote locations are prefixed with the location, e.g. http://remote:7200/TestRepo.
+func (s *Server) Repository(id string) (graphdb.Repository, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	repo, ok := s.repositories[id]
+	if !ok {
+		return graphdb.Repository{}, false
+	}
+	return repo.info, true
+}
+
+// UploadedFile returns the content of a file uploaded for a repository configuration, given its path on the server.
+func (s *Server) UploadedFile(path string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	content, ok := s.files[path]
+	return content, ok
+}
+
+// RepositoryUpdates returns how many times the repository with the given ID was edited in place.
+func (s *Server) RepositoryUpdates(id string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if repo, ok := s.repositories[id]; ok {
+		return repo.updates
+	}
+	return 0
+}
+
+// RepositoryState returns the state of the repository with the given ID, one of the graphdb.RepositoryState constants.
+func (s *Server) RepositoryState(id string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if repo, ok := s.repositories[id]; ok {
+		return repo.state
+	}
+	return ""
+}
+
+// RepositoryRestarts returns how many times the repository with the given ID was restarted.
+func (s *Server) RepositoryRestarts(id string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if repo, ok := s.repositories[id]; ok {
+		return repo.restarts
+	}
+	return 0
+}
+
+// SetRepositoryState changes the state of a repository behind the client's back, to simulate out-of-band changes.
+func (s *Server) SetRepositoryState(id string, state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if repo, ok := s.repositories[id]; ok {
+		repo.state = state
+	}
+}
+
+// SetRepositorySize sets the number of statements in a repository, as if data had been loaded into it.
+func (s *Server) SetRepositorySize(id string, explicit int64, inferred int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if repo, ok := s.repositories[id]; ok {
+		repo.size = graphdb.RepositorySize{Total: explicit + inferred, Explicit: explicit, Inferred: inferred}
+	}
+}
+
+// SetRepositoryParam changes a parameter of a repository behind the client's back, to simulate out-of-band changes.
+func (s *Server) SetRepositoryParam(id string, name string, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if repo, ok := s.repositories[id]; ok {
+		if repo.info.Params == nil {
+			repo.info.Params = map[string]graphdb.RepositoryParam{}
+		}
+		repo.info.Params[name] = graphdb.RepositoryParam{Name: name, Label: name, Value: value}
+	}
+}
+
+// RemoveRepository deletes a repository behind the client's back, to simulate out-of-band changes.
+func (s *Server) RemoveRepository(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.repositories, id)
+}
+
+// RemoveUser deletes a user behind the client's back, to simulate out-of-band changes.
+func (s *Server) RemoveUser(username string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.users, username)
+}
+
+// AddServerFile makes a file available for import into the given repository from the server's import directory.
+func (s *Server) AddServerFile(repositoryID string, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.serverFiles[repositoryID] = append(s.serverFiles[repositoryID], graphdb.ImportResource{Name: name, Type: "file", Status: "NONE"})
+}
+
+// SetClusterStatus turns the server into a member of a cluster made of the given nodes.
+func (s *Server) SetClusterStatus(nodes []graphdb.ClusterNodeStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cluster = nodes
+}
+
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := s.authenticatedUser(r); s.security && !ok {
+			writeError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// authenticatedUser returns the user whose credentials were sent with the request, if they are valid.
+func (s *Server) authenticatedUser(r *http.Request) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if username, password, ok := r.BasicAuth(); ok {
+		expected, exists := s.passwords[username]
+		return username, exists && expected == password
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "GDB "); ok {
+		username, err := base64.StdEncoding.DecodeString(token)
+		_, exists := s.users[string(username)]
+		return string(username), err == nil && exists
+	}
+	return "", false
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	var login struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&login); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	expected, exists := s.passwords[login.Username]
+	user := s.users[login.Username]
+	s.mu.Unlock()
+	if !exists || expected != login.Password {
+		writeError(w, http.StatusUnauthorized, "Bad credentials")
+		return
+	}
+
+	w.Header().Set("Authorization", "GDB "+base64.StdEncoding.EncodeToString([]byte(login.Username)))
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *Server) handleSecurity(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.security)
+}
+
+func (s *Server) handleAuthenticatedUser(w http.ResponseWriter, r *http.Request) {
+	username, ok := s.authenticatedUser(r)
+	if !ok {
+		// Without security, every request acts as the admin
+		username = AdminUsername
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.users[username])
+}
+
+func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"productType":    s.productType,
+		"productVersion": s.version,
+	})
+}
+
+// repositoryKey identifies a repository across locations.
+func repositoryKey(location string, id string) string {
+	if location == "" {
+		return id
+	}
+	return location + "/" + id
+}
+
+// location returns the location a request is addressed to, without a trailing slash, writing an error if it is not
+// attached.
+func (s *Server) location(w http.ResponseWriter, r *http.Request) (string, bool) {
+	location := strings.TrimSuffix(r.URL.Query().Get("location"), "/")
+	if location != "" && !s.locations[location] {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Location %s is not attached.", location))
+		return "", false
+	}
+	return location, true
+}
+
+func (s *Server) handleRepositories(w http.ResponseWriter, r *http.Request) {
+	location, ok := s.location(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		list := []graphdb.RepositorySummary{}
+		for _, repo := range s.repositories {
+			if repo.info.Location != location {
+				continue
+			}
+			list = append(list, graphdb.RepositorySummary{
+				Name:        repo.info.ID,
+				Title:       repo.info.Title,
+				Uri:         s.URL + "/repositories/" + repo.info.ID,
+				ExternalUrl: s.URL + "/repositories/" + repo.info.ID,
+				Type:        repo.info.Type,
+				Local:       location == "",
+				State:       repo.listedState(),
+			})
+		}
+		s.mu.Unlock()
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+		writeJSON(w, http.StatusOK, list)
+	case http.MethodPost:
+		s.createRepository(w, r, location)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) createRepository(w http.ResponseWriter, r *http.Request, location string) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var info graphdb.Repository
+		if err := json.NewDecoder(r.Body).Decode(&info); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.addRepository(w, location, info, "")
+		return
+	}
+
+	file, _, err := r.FormFile("config")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Missing config file: "+err.Error())
+		return
+	}
+	defer file.Close()
+	config, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	info, err := graphdb.ParseRepositoryConfig(string(config))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// GraphDB keys the parameters of configuration files by the camelCase form of their local names
+	params := map[string]graphdb.RepositoryParam{}
+	for name, param := range info.Params {
+		params[camelCase(name)] = param
+	}
+	info.Params = params
+	// Like GraphDB, return the members of a federation as a structured parameter
+	if info.Type == "fedx" {
+		members, err := graphdb.ParseFedXMembers(string(config))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if info.Params[graphdb.FedXMembersParam], err = graphdb.NewFedXMembersParam(members); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	s.addRepository(w, location, info, string(config))
+}
+
+func (s *Server) addRepository(w http.ResponseWriter, location string, info graphdb.Repository, config string) {
+	if info.ID == "" {
+		writeError(w, http.StatusBadRequest, "Repository ID is missing from the configuration")
+		return
+	}
+	if info.Type == "" {
+		info.Type = "graphdb"
+	}
+	info.Location = location
+	info.Params = knownParams(info.Params)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := repositoryKey(location, info.ID)
+	if _, exists := s.repositories[key]; exists {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Repository %s already exists.", info.ID))
+		return
+	}
+	s.repositories[key] = &repository{info: info, config: config, state: graphdb.RepositoryStateRunning, starting: s.startupPolls}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) handleRepository(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/rest/repositories/"), "/", 2)
+	id := parts[0]
+	location, ok := s.location(w, r)
+	if !ok {
+		return
+	}
+	if id == "uploadFile" && len(parts) == 1 {
+		s.handleUploadFile(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	repo, exists := s.repositories[repositoryKey(location, id)]
+	s.mu.Unlock()
+	if !exists {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Repository %s does not exist.", id))
+		return
+	}
+
+	if len(parts) == 2 && parts[1] == "download-ttl" {
+		s.handleDownloadConfig(w, r, repo)
+		return
+	}
+	if len(parts) == 2 && parts[1] == "size" && r.Method == http.MethodGet {
+		s.mu.Lock()
+		size := repo.size
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, size)
+		return
+	}
+	if len(parts) == 2 && (parts[1] == "restart" || parts[1] == "shutdown") {
+		s.handleRepositoryState(w, r, repo, parts[1])
+		return
+	}
+	if len(parts) == 2 {
+		s.handleImport(w, r, id, parts[1])
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.Lock()
+		info := repo.info
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, info)
+	case http.MethodPut:
+		s.updateRepository(w, r, repo)
+	case http.MethodDelete:
+		s.mu.Lock()
+		delete(s.repositories, repositoryKey(location, id))
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// knownParams returns the parameters GraphDB would keep, naming and labelling them after their keys. Like GraphDB, it
+// ignores parameters which are not keyed in camelCase, such as query-timeout instead of queryTimeout.
+func knownParams(params map[string]graphdb.RepositoryParam) map[string]graphdb.RepositoryParam {
+	known := map[string]graphdb.RepositoryParam{}
+	for name, param := range params {
+		if camelCase(name) != name {
+			continue
+		}
+		param.Name = name
+		if param.Label == "" {
+			param.Label = name
+		}
+		known[name] = param
+	}
+	return known
+}
+
+// camelCase converts the local name of a parameter in a configuration file to its key in the JSON form of a
+// repository, e.g. entity-id-size to entityIdSize.
+func camelCase(name string) string {
+	parts := strings.Split(name, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// listedState returns the state of the repository in the list of repositories, counting down its startup.
+func (repo *repository) listedState() string {
+	if repo.starting > 0 && repo.state == graphdb.RepositoryStateRunning {
+		repo.starting--
+		return graphdb.RepositoryStateStarting
+	}
+	return repo.state
+}
+
+// handleRepositoryState restarts or shuts down a repository. The change takes effect immediately.
+func (s *Server) handleRepositoryState(w http.ResponseWriter, r *http.Request, repo *repository, action string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	s.mu.Lock()
+	if action == "restart" {
+		repo.state = graphdb.RepositoryStateRunning
+		repo.restarts++
+		repo.starting = s.startupPolls
+	} else {
+		repo.state = graphdb.RepositoryStateInactive
+	}
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleUploadFile stores a file used by a repository configuration, answering with its path on the server.
+func (s *Server) handleUploadFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	file, header, err := r.FormFile("uploadFile")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Missing file: "+err.Error())
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	path := fmt.Sprintf("/opt/graphdb/home/data/upload/%d-%s", len(s.files)+1, header.Filename)
+	s.files[path] = string(content)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(path))
+}
+
+// FedXMembers returns the members of a FedX repository on the server, which are returned as a structured parameter.
+func FedXMembers(repo graphdb.Repository) ([]graphdb.FedXMember, error) {
+	param, ok := repo.Params[graphdb.FedXMembersParam]
+	if !ok || param.Raw == nil {
+		return nil, nil
+	}
+	var members []graphdb.FedXMember
+	if err := json.Unmarshal(param.Raw, &members); err != nil {
+		return nil, fmt.Errorf("Invalid FedX members: %w", err)
+	}
+	return members, nil
+}
+
+// handleDownloadConfig returns the live configuration of a repository. Like GraphDB, it reflects edits, so it is
+// rendered from the parameters of the repository rather than returning the uploaded configuration.
+func (s *Server) handleDownloadConfig(w http.ResponseWriter, r *http.Request, repo *repository) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	s.mu.Lock()
+	config := repo.config
+	switch repo.info.Type {
+	case "graphdb":
+		config = graphdb.FormatRepositoryConfig(repo.info)
+	case "fedx":
+		members, err := FedXMembers(repo.info)
+		if err != nil {
+			s.mu.Unlock()
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		config = graphdb.FormatFedXConfig(repo.info, members)
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/turtle")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(config))
+}
+
+// updateRepository edits a repository, which like GraphDB cannot change its type or its ID.
+func (s *Server) updateRepository(w http.ResponseWriter, r *http.Request, repo *repository) {
+	var info graphdb.Repository
+	if err := json.NewDecoder(r.Body).Decode(&info); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if info.ID != repo.info.ID || info.Type != repo.info.Type {
+		writeError(w, http.StatusBadRequest, "The repository ID and type cannot be changed")
+		return
+	}
+	repo.info.Title = info.Title
+	repo.info.Params = knownParams(info.Params)
+	repo.updates++
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleImport(w http.ResponseWriter, r *http.Request, id string, resource string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case resource == "import/server" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, nonNil(s.serverFiles[id]))
+	case resource == "import/server" && r.Method == http.MethodPost:
+		var request graphdb.ServerImportRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for _, name := range request.FileNames {
+			found := false
+			for i, f := range s.serverFiles[id] {
+				if f.Name == name {
+					s.serverFiles[id][i].Status = "DONE"
+					found = true
+				}
+			}
+			if !found {
+				writeError(w, http.StatusNotFound, fmt.Sprintf("File %s does not exist.", name))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusAccepted)
+	case resource == "import/upload" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, nonNil(s.uploads[id]))
+	case resource == "import/upload/url" && r.Method == http.MethodPost:
+		var settings graphdb.ImportSettings
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.uploads[id] = append(s.uploads[id], graphdb.ImportResource{Name: settings.Data, Type: "url", Format: settings.Format, Status: "DONE"})
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (s *Server) handleUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	s.mu.Lock()
+	users := []graphdb.User{}
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+	s.mu.Unlock()
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *Server) handleUser(w http.ResponseWriter, r *http.Request) {
+	username := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	if username == "" {
+		s.handleUsers(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, exists := s.users[username]
+
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("User %s not found.", username))
+			return
+		}
+		writeJSON(w, http.StatusOK, existing)
+	case http.MethodPost, http.MethodPut:
+		if r.Method == http.MethodPost && exists {
+			writeError(w, http.StatusBadRequest, "An account with the given username already exists.")
+			return
+		}
+		if r.Method == http.MethodPut && !exists {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("User %s not found.", username))
+			return
+		}
+		var user graphdb.User
+		if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.passwords[username] = user.Password
+		s.users[username] = graphdb.User{Username: username, Authorities: user.Authorities}
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+	case http.MethodDelete:
+		if !exists {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("User %s not found.", username))
+			return
+		}
+		delete(s.users, username)
+		delete(s.passwords, username)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+func (s *Server) handleCluster(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cluster == nil {
+		writeError(w, http.StatusNotFound, "No cluster configured.")
+		return
+	}
+	switch strings.TrimPrefix(r.URL.Path, "/rest/cluster/") {
+	case "config":
+		config := graphdb.ClusterConfig{}
+		for _, n := range s.cluster {
+			config.Nodes = append(config.Nodes, n.Address)
+		}
+		writeJSON(w, http.StatusOK, config)
+	case "group/status":
+		writeJSON(w, http.StatusOK, s.cluster)
+	case "node/status":
+		writeJSON(w, http.StatusOK, s.cluster[0])
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func nonNil(resources []graphdb.ImportResource) []graphdb.ImportResource {
+	if resources == nil {
+		return []graphdb.ImportResource{}
+	}
+	return resources
+}
+
+// decompress transparently decodes gzip compressed requests.
+func decompress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			body, err := gzip.NewReader(r.Body)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			r.Body = body
+			r.Header.Del("Content-Encoding")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
@@ -0,0 +1,440 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdbtest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
+)
+
+const testRepositoryConfig = `
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+@prefix rep: <http://www.openrdf.org/config/repository#> .
+
+[] a rep:Repository ;
+    rep:repositoryID "TestRepo" ;
+    rdfs:label "Test repository" .
+`
+
+func newClient(t *testing.T, server *Server, opts ...graphdb.Option) *graphdb.Client {
+	t.Helper()
+	client, err := graphdb.NewClient(server.URL, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestRepositoryLifecycle(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	if err := client.CreateRepository(ctx, strings.NewReader(testRepositoryConfig)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateRepository(ctx, strings.NewReader(testRepositoryConfig)); err == nil {
+		t.Fatal("Expected creating a duplicate repository to fail")
+	}
+
+	repo, err := client.GetRepository(ctx, "TestRepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Title != "Test repository" || repo.Type != "graphdb" {
+		t.Fatalf("Unexpected repository: %+v", repo)
+	}
+
+	repo.Title = "Renamed"
+	if err := client.UpdateRepository(ctx, "TestRepo", repo); err != nil {
+		t.Fatal(err)
+	}
+	if repo, _ := server.Repository("TestRepo"); repo.Title != "Renamed" || server.RepositoryUpdates("TestRepo") != 1 {
+		t.Fatalf("Expected repository to be updated. Got: %+v", repo)
+	}
+	repo.Type = "fedx"
+	if err := client.UpdateRepository(ctx, "TestRepo", repo); err == nil {
+		t.Fatal("Expected changing the repository type to fail")
+	}
+
+	server.SetRepositoryParam("TestRepo", "ruleset", "owl-horst")
+	config, err := client.GetRepositoryConfig(ctx, "TestRepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if live, err := graphdb.ParseRepositoryConfig(config); err != nil || live.Title != "Renamed" || live.Params["ruleset"].Value != "owl-horst" {
+		t.Fatalf("Unexpected live configuration: %+v, %v", live, err)
+	}
+
+	repos, err := client.GetRepositories(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].Name != "TestRepo" {
+		t.Fatalf("Unexpected repositories: %+v", repos)
+	}
+
+	if err := client.DeleteRepository(ctx, "TestRepo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetRepository(ctx, "TestRepo"); !graphdb.IsNotFound(err) {
+		t.Fatalf("Expected deleted repository to be not found. Got: %v", err)
+	}
+}
+
+func TestRepositoryParamKeys(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	config := `
+@prefix rep: <http://www.openrdf.org/config/repository#> .
+@prefix sr: <http://www.openrdf.org/config/repository/sail#> .
+@prefix graphdb: <http://www.ontotext.com/config/graphdb#> .
+
+[] a rep:Repository ;
+    rep:repositoryID "TestRepo" ;
+    rep:repositoryImpl [
+        rep:repositoryType "graphdb:SailRepository" ;
+        sr:sailImpl [ graphdb:query-timeout "30" ; graphdb:entity-id-size "32" ; graphdb:base-URL "http://example.org#" ;
+            graphdb:fts-indexes ("default" "iri") ]
+    ] .
+`
+	if err := client.CreateRepository(ctx, strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := client.GetRepository(ctx, "TestRepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range map[string]string{"queryTimeout": "30", "entityIdSize": "32", "baseURL": "http://example.org#", "ftsIndexes": "default, iri"} {
+		if param, ok := repo.Params[key]; !ok || param.Name != key || param.Value != value {
+			t.Fatalf("Expected parameter %s to be keyed in camelCase with value %s. Got: %+v", key, value, repo.Params)
+		}
+	}
+
+	// Like GraphDB, parameters keyed by their local names are ignored
+	repo.Params["query-timeout"] = graphdb.RepositoryParam{Name: "query-timeout", Value: "60"}
+	if err := client.UpdateRepository(ctx, "TestRepo", repo); err != nil {
+		t.Fatal(err)
+	}
+	if live, _ := server.Repository("TestRepo"); live.Params["queryTimeout"].Value != "30" || len(live.Params) != 4 {
+		t.Fatalf("Expected the hyphenated parameter to be ignored. Got: %+v", live.Params)
+	}
+}
+
+func TestRepositoryState(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	if err := client.CreateRepository(ctx, strings.NewReader(testRepositoryConfig)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.ShutdownRepository(ctx, "TestRepo"); err != nil {
+		t.Fatal(err)
+	}
+	if state, err := client.GetRepositoryState(ctx, "TestRepo"); err != nil || state != graphdb.RepositoryStateInactive {
+		t.Fatalf("Expected the repository to be inactive. Got: %s, %v", state, err)
+	}
+	if err := client.RestartRepository(ctx, "TestRepo"); err != nil {
+		t.Fatal(err)
+	}
+	if state, err := client.GetRepositoryState(ctx, "TestRepo"); err != nil || state != graphdb.RepositoryStateRunning {
+		t.Fatalf("Expected the repository to be running. Got: %s, %v", state, err)
+	}
+	if restarts := server.RepositoryRestarts("TestRepo"); restarts != 1 {
+		t.Fatalf("Expected a single restart. Got: %d", restarts)
+	}
+	server.SetRepositorySize("TestRepo", 10, 5)
+	if size, err := client.GetRepositorySize(ctx, "TestRepo"); err != nil || size != (graphdb.RepositorySize{Total: 15, Explicit: 10, Inferred: 5}) {
+		t.Fatalf("Unexpected repository size: %+v, %v", size, err)
+	}
+	if _, err := client.GetRepositoryState(ctx, "Missing"); !graphdb.IsNotFound(err) {
+		t.Fatalf("Expected the state of a missing repository to be not found. Got: %v", err)
+	}
+}
+
+func TestRepositoryStartup(t *testing.T) {
+	server := NewServer(WithRepositoryStartup(2))
+	defer server.Close()
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	if err := client.CreateRepository(ctx, strings.NewReader(testRepositoryConfig)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{graphdb.RepositoryStateStarting, graphdb.RepositoryStateStarting, graphdb.RepositoryStateRunning}
+	for i, w := range want {
+		if state, err := client.GetRepositoryState(ctx, "TestRepo"); err != nil || state != w {
+			t.Fatalf("Unexpected state after %d polls. Wanted: %s. Got: %s, %v", i, w, state, err)
+		}
+	}
+	if err := client.RestartRepository(ctx, "TestRepo"); err != nil {
+		t.Fatal(err)
+	}
+	if state, err := client.GetRepositoryState(ctx, "TestRepo"); err != nil || state != graphdb.RepositoryStateStarting {
+		t.Fatalf("Expected the repository to be starting after a restart. Got: %s, %v", state, err)
+	}
+}
+
+func TestRepositoryLocations(t *testing.T) {
+	remote := "http://remote:7200"
+	server := NewServer(WithLocation(remote))
+	defer server.Close()
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	if err := client.InLocation(remote).CreateRepository(ctx, strings.NewReader(testRepositoryConfig)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetRepository(ctx, "TestRepo"); !graphdb.IsNotFound(err) {
+		t.Fatalf("Expected the repository to only exist in the remote location. Got: %v", err)
+	}
+	repo, err := client.InLocation(remote).GetRepository(ctx, "TestRepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Location != remote {
+		t.Fatalf("Unexpected location: %s", repo.Location)
+	}
+	if _, ok := server.Repository(remote + "/TestRepo"); !ok {
+		t.Fatal("Expected the repository to be created in the remote location")
+	}
+
+	// Locations are matched regardless of a trailing slash
+	if _, err := client.InLocation(remote+"/").GetRepository(ctx, "TestRepo"); err != nil {
+		t.Fatalf("Expected the location to match with a trailing slash. Got: %v", err)
+	}
+
+	if _, err := client.InLocation("http://unknown:7200").GetRepositories(ctx); err == nil {
+		t.Fatal("Expected a location which is not attached to be rejected")
+	}
+}
+
+func TestRepositoryFiles(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	path, err := client.UploadRepositoryFile(ctx, "Virtual.obda", strings.NewReader("[MappingDeclaration] @collection [[\n]]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content, ok := server.UploadedFile(path); !ok || !strings.HasPrefix(content, "[MappingDeclaration]") {
+		t.Fatalf("Unexpected uploaded file at %s: %q", path, content)
+	}
+
+	repo := graphdb.Repository{
+		ID:         "Virtual",
+		Title:      "Virtual repository",
+		Type:       "ontop",
+		SesameType: "graphdb:OntopRepository",
+		Params:     map[string]graphdb.RepositoryParam{"obdaFile": {Name: "obdaFile", Value: path}},
+	}
+	if err := client.CreateRepositoryJSON(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.GetRepository(ctx, "Virtual")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != "ontop" || got.Title != "Virtual repository" || got.Params["obdaFile"].Value != path {
+		t.Fatalf("Unexpected repository: %+v", got)
+	}
+	if err := client.CreateRepositoryJSON(ctx, repo); err == nil {
+		t.Fatal("Expected creating an existing repository to fail")
+	}
+}
+
+func TestUserLifecycle(t *testing.T) {
+	server := NewServer(WithSecurity())
+	defer server.Close()
+	client := newClient(t, server, graphdb.WithBasicAuth(AdminUsername, AdminPassword))
+	ctx := context.Background()
+
+	user := graphdb.User{Username: "TestUser", Password: "secret", Authorities: []string{"ROLE_USER"}}
+	if err := client.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	user.Authorities = []string{"ROLE_REPO_MANAGER"}
+	if err := client.UpdateUser(ctx, "TestUser", user); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.GetUser(ctx, "TestUser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Authorities[0] != "ROLE_REPO_MANAGER" || got.Password != "" {
+		t.Fatalf("Unexpected user: %+v", got)
+	}
+
+	users, err := client.GetUsers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("Expected the admin and test users. Got: %+v", users)
+	}
+
+	// The new user can log in with their own credentials
+	userClient := newClient(t, server, graphdb.WithTokenAuth("TestUser", "secret"))
+	if _, err := userClient.GetRepositories(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.DeleteUser(ctx, "TestUser"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.DeleteUser(ctx, "TestUser"); !graphdb.IsNotFound(err) {
+		t.Fatalf("Expected deleting a missing user to be not found. Got: %v", err)
+	}
+}
+
+func TestSecurity(t *testing.T) {
+	server := NewServer(WithSecurity())
+	defer server.Close()
+	ctx := context.Background()
+
+	if _, err := newClient(t, server).GetRepositories(ctx); err == nil {
+		t.Fatal("Expected anonymous request to fail")
+	}
+	if _, err := newClient(t, server, graphdb.WithBasicAuth(AdminUsername, "wrong")).GetRepositories(ctx); err == nil {
+		t.Fatal("Expected request with invalid credentials to fail")
+	}
+	if _, err := newClient(t, server, graphdb.WithTokenAuth(AdminUsername, AdminPassword)).GetRepositories(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestImport(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	if err := client.CreateRepository(ctx, strings.NewReader(testRepositoryConfig)); err != nil {
+		t.Fatal(err)
+	}
+	server.AddServerFile("TestRepo", "data.ttl")
+
+	if err := client.ImportServerFiles(ctx, "TestRepo", graphdb.ServerImportRequest{FileNames: []string{"data.ttl"}}); err != nil {
+		t.Fatal(err)
+	}
+	files, err := client.GetServerImports(ctx, "TestRepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Status != "DONE" {
+		t.Fatalf("Unexpected import status: %+v", files)
+	}
+
+	if err := client.ImportFromURL(ctx, "TestRepo", graphdb.ImportSettings{Data: "http://example.com/data.ttl"}); err != nil {
+		t.Fatal(err)
+	}
+	uploads, err := client.GetUploadImports(ctx, "TestRepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uploads) != 1 || uploads[0].Name != "http://example.com/data.ttl" {
+		t.Fatalf("Unexpected uploads: %+v", uploads)
+	}
+}
+
+func TestCluster(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	if _, err := client.GetClusterGroupStatus(ctx); !graphdb.IsNotFound(err) {
+		t.Fatalf("Expected a standalone server to have no cluster. Got: %v", err)
+	}
+
+	server.SetClusterStatus([]graphdb.ClusterNodeStatus{
+		{Address: "node1:7300", Endpoint: "http://node1:7200", NodeState: graphdb.NodeStateLeader},
+		{Address: "node2:7300", Endpoint: "http://node2:7200", NodeState: graphdb.NodeStateFollower},
+	})
+	nodes, err := client.GetClusterGroupStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 2 || nodes[0].NodeState != graphdb.NodeStateLeader {
+		t.Fatalf("Unexpected cluster status: %+v", nodes)
+	}
+	config, err := client.GetClusterConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Nodes) != 2 {
+		t.Fatalf("Unexpected cluster config: %+v", config)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	server := NewServer(WithVersion("9.11.0", "ee"))
+	defer server.Close()
+	client := newClient(t, server)
+	ctx := context.Background()
+
+	info, err := client.DetectServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Edition != graphdb.EditionEnterprise || info.Version.Major != 9 {
+		t.Fatalf("Unexpected server info: %s", info)
+	}
+	// Users are served under the GraphDB 9.x path, which the client now uses
+	if err := client.CreateUser(ctx, graphdb.User{Username: "TestUser", Password: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuthenticatedUser(t *testing.T) {
+	ctx := context.Background()
+
+	insecure := NewServer()
+	defer insecure.Close()
+	if enabled, err := newClient(t, insecure).SecurityEnabled(ctx); err != nil || enabled {
+		t.Fatalf("Expected security to be disabled. Got: %v, %v", enabled, err)
+	}
+
+	server := NewServer(WithSecurity())
+	defer server.Close()
+	client := newClient(t, server, graphdb.WithTokenAuth(AdminUsername, AdminPassword))
+	if enabled, err := client.SecurityEnabled(ctx); err != nil || !enabled {
+		t.Fatalf("Expected security to be enabled. Got: %v, %v", enabled, err)
+	}
+	user, err := client.GetAuthenticatedUser(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Username != AdminUsername {
+		t.Fatalf("Unexpected authenticated user: %+v", user)
+	}
+	if _, err := newClient(t, server).GetAuthenticatedUser(ctx); !graphdb.IsUnauthorized(err) {
+		t.Fatalf("Expected anonymous request to be unauthorized. Got: %v", err)
+	}
+}
+
+func TestCompressedUpload(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := newClient(t, server, graphdb.WithUploadCompression())
+
+	if err := client.CreateRepository(context.Background(), strings.NewReader(testRepositoryConfig)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := server.Repository("TestRepo"); !ok {
+		t.Fatal("Expected repository to be created from a compressed upload")
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ImportAPI loads data into repositories.
+// Imports run asynchronously in GraphDB, so callers should poll the import status to know when they complete.
+type ImportAPI interface {
+	// GetServerImports lists the files in the server's import directory, along with their import status.
+	GetServerImports(ctx context.Context, repositoryID string) ([]ImportResource, error)
+	// ImportServerFiles starts importing files from the server's import directory.
+	ImportServerFiles(ctx context.Context, repositoryID string, request ServerImportRequest) error
+	// GetUploadImports lists the data which was uploaded or imported from a URL, along with its import status.
+	GetUploadImports(ctx context.Context, repositoryID string) ([]ImportResource, error)
+	// ImportFromURL starts importing the data at settings.Data into the repository.
+	ImportFromURL(ctx context.Context, repositoryID string, settings ImportSettings) error
+}
+
+func (c *Client) GetServerImports(ctx context.Context, repositoryID string) ([]ImportResource, error) {
+	var resources []ImportResource
+
+	req, err := c.newRequest(ctx, http.MethodGet, "repositories/"+repositoryID+"/import/server", nil)
+	if err != nil {
+		return resources, err
+	}
+	if err := c.do(req, &resources, http.StatusOK); err != nil {
+		return resources, fmt.Errorf("Failed to list server imports for repository %s: %w", repositoryID, err)
+	}
+	return resources, nil
+}
+
+func (c *Client) ImportServerFiles(ctx context.Context, repositoryID string, request ServerImportRequest) error {
+	req, err := c.newRequest(ctx, http.MethodPost, "repositories/"+repositoryID+"/import/server", request)
+	if err != nil {
+		return err
+	}
+	if err := c.do(req, nil, http.StatusOK, http.StatusAccepted); err != nil {
+		return fmt.Errorf("Failed to import server files into repository %s: %w", repositoryID, err)
+	}
+	return nil
+}
+
+func (c *Client) GetUploadImports(ctx context.Context, repositoryID string) ([]ImportResource, error) {
+	var resources []ImportResource
+
+	req, err := c.newRequest(ctx, http.MethodGet, "repositories/"+repositoryID+"/import/upload", nil)
+	if err != nil {
+		return resources, err
+	}
+	if err := c.do(req, &resources, http.StatusOK); err != nil {
+		return resources, fmt.Errorf("Failed to list uploads for repository %s: %w", repositoryID, err)
+	}
+	return resources, nil
+}
+
+func (c *Client) ImportFromURL(ctx context.Context, repositoryID string, settings ImportSettings) error {
+	req, err := c.newRequest(ctx, http.MethodPost, "repositories/"+repositoryID+"/import/upload/url", settings)
+	if err != nil {
+		return err
+	}
+	if err := c.do(req, nil, http.StatusOK, http.StatusAccepted); err != nil {
+		return fmt.Errorf("Failed to import %s into repository %s: %w", settings.Data, repositoryID, err)
+	}
+	return nil
+}
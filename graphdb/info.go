@@ -0,0 +1,189 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// InfoAPI describes the GraphDB server a client is connected to.
+type InfoAPI interface {
+	// DetectServer fetches the version and edition of the server and stores them on the client,
+	// which then uses the REST paths of that version.
+	DetectServer(ctx context.Context) (ServerInfo, error)
+	// ServerInfo returns the server details stored by DetectServer, and false if it has not been called yet.
+	ServerInfo() (ServerInfo, bool)
+}
+
+// Edition is a GraphDB product edition.
+type Edition string
+
+const (
+	EditionFree       Edition = "Free"
+	EditionStandard   Edition = "SE"
+	EditionEnterprise Edition = "EE"
+)
+
+// parseEdition converts the product type reported by GraphDB into an Edition.
+// GraphDB 9.x reports free, se or ee, while later versions report free or enterprise.
+func parseEdition(productType string) Edition {
+	switch strings.ToLower(productType) {
+	case "free":
+		return EditionFree
+	case "se", "standard":
+		return EditionStandard
+	case "ee", "enterprise":
+		return EditionEnterprise
+	default:
+		return Edition(productType)
+	}
+}
+
+// Version is a GraphDB release version.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses a version such as 10.4.0 or 10.5.0-RC1, ignoring any pre-release suffix.
+func ParseVersion(version string) (Version, error) {
+	var v Version
+	core, _, _ := strings.Cut(version, "-")
+	parts := strings.Split(core, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return v, fmt.Errorf("Invalid GraphDB version %q", version)
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("Invalid GraphDB version %q", version)
+		}
+		numbers[i] = n
+	}
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// AtLeast reports whether v is the same as or newer than other.
+func (v Version) AtLeast(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	return v.Patch >= other.Patch
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// ServerInfo is the version and edition of a GraphDB server.
+type ServerInfo struct {
+	Version Version
+	Edition Edition
+}
+
+func (s ServerInfo) String() string {
+	return fmt.Sprintf("GraphDB %s %s", s.Edition, s.Version)
+}
+
+// Supports reports whether the server provides the given feature.
+func (s ServerInfo) Supports(f Feature) bool {
+	if !s.Version.AtLeast(f.MinVersion) {
+		return false
+	}
+	if len(f.Editions) == 0 {
+		return true
+	}
+	for _, e := range f.Editions {
+		if s.Edition == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Feature is a capability which is only provided by some versions or editions of GraphDB.
+type Feature struct {
+	Name       string
+	MinVersion Version
+	// Editions providing the feature, or all of them if empty
+	Editions []Edition
+}
+
+// String describes the feature and its requirements, e.g. "cluster (requires GraphDB EE 10.0.0 or later)".
+func (f Feature) String() string {
+	editions := make([]string, len(f.Editions))
+	for i, e := range f.Editions {
+		editions[i] = string(e)
+	}
+	requirement := "GraphDB"
+	if len(editions) > 0 {
+		requirement += " " + strings.Join(editions, " or ")
+	}
+	return fmt.Sprintf("%s (requires %s %s or later)", f.Name, requirement, f.MinVersion)
+}
+
+var (
+	// FeatureCluster is the cluster management API introduced in GraphDB 10.
+	FeatureCluster = Feature{Name: "cluster", MinVersion: Version{Major: 10}, Editions: []Edition{EditionEnterprise}}
+	// FeatureFedX is the FedX federation repository type.
+	FeatureFedX = Feature{Name: "FedX federation", MinVersion: Version{Major: 10}}
+	// FeatureOntop is the Ontop virtual repository type.
+	FeatureOntop = Feature{Name: "Ontop virtual repositories", MinVersion: Version{Major: 9, Minor: 8}}
+)
+
+// versionResponse is returned by the info/version endpoint.
+type versionResponse struct {
+	ProductType    string `json:"productType"`
+	ProductVersion string `json:"productVersion"`
+}
+
+func (c *Client) DetectServer(ctx context.Context) (ServerInfo, error) {
+	var info ServerInfo
+	var data versionResponse
+
+	req, err := c.newRequest(ctx, http.MethodGet, "info/version", nil)
+	if err != nil {
+		return info, err
+	}
+	if err := c.do(req, &data, http.StatusOK); err != nil {
+		return info, fmt.Errorf("Failed to get server version: %w", err)
+	}
+
+	version, err := ParseVersion(data.ProductVersion)
+	if err != nil {
+		return info, err
+	}
+	info = ServerInfo{Version: version, Edition: parseEdition(data.ProductType)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.info = &info
+	return info, nil
+}
+
+func (c *Client) ServerInfo() (ServerInfo, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.info == nil {
+		return ServerInfo{}, false
+	}
+	return *c.info, true
+}
+
+// legacyAPI reports whether the client is connected to a GraphDB 9.x server, which uses different REST paths.
+// Servers of an unknown version are assumed to be current.
+func (c *Client) legacyAPI() bool {
+	info, ok := c.ServerInfo()
+	return ok && info.Version.Major < 10
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	valid := map[string]Version{
+		"10.4.0":     {Major: 10, Minor: 4},
+		"9.11.2":     {Major: 9, Minor: 11, Patch: 2},
+		"11.0":       {Major: 11},
+		"10.5.0-RC1": {Major: 10, Minor: 5},
+	}
+	for s, want := range valid {
+		got, err := ParseVersion(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("Unexpected version for %s. Wanted: %s. Got: %s", s, want, got)
+		}
+	}
+
+	for _, s := range []string{"", "10", "10.x.0", "1.2.3.4"} {
+		if _, err := ParseVersion(s); err == nil {
+			t.Fatalf("Expected %q to be invalid", s)
+		}
+	}
+}
+
+func TestServerInfoSupports(t *testing.T) {
+	free10 := ServerInfo{Version: Version{Major: 10, Minor: 4}, Edition: EditionFree}
+	ee9 := ServerInfo{Version: Version{Major: 9, Minor: 11}, Edition: EditionEnterprise}
+	ee10 := ServerInfo{Version: Version{Major: 10}, Edition: EditionEnterprise}
+
+	if free10.Supports(FeatureCluster) || ee9.Supports(FeatureCluster) || !ee10.Supports(FeatureCluster) {
+		t.Fatal("Expected the cluster to require GraphDB EE 10")
+	}
+	if !free10.Supports(FeatureFedX) || ee9.Supports(FeatureFedX) {
+		t.Fatal("Expected FedX to require GraphDB 10 of any edition")
+	}
+	if want := "cluster (requires GraphDB EE 10.0.0 or later)"; FeatureCluster.String() != want {
+		t.Fatalf("Unexpected feature description. Wanted: %s. Got: %s", want, FeatureCluster.String())
+	}
+}
+
+func TestDetectServer(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/rest/info/version":
+			_, _ = w.Write([]byte(`{"productType": "se", "productVersion": "9.11.2", "sesame": "3.7.7"}`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, server.URL)
+	ctx := context.Background()
+	if _, ok := client.ServerInfo(); ok {
+		t.Fatal("Expected server info to be unknown before detection")
+	}
+	info, err := client.DetectServer(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Edition != EditionStandard || info.Version != (Version{Major: 9, Minor: 11, Patch: 2}) {
+		t.Fatalf("Unexpected server info: %s", info)
+	}
+	if stored, ok := client.ServerInfo(); !ok || stored != info {
+		t.Fatalf("Expected server info to be stored on the client. Got: %s", stored)
+	}
+
+	// GraphDB 9.x uses a different path for users
+	if _, err := client.GetUsers(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if want := "/rest/security/user/"; paths[len(paths)-1] != want {
+		t.Fatalf("Unexpected users path. Wanted: %s. Got: %s", want, paths[len(paths)-1])
+	}
+}
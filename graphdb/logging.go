@@ -0,0 +1,339 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// logSubsystem is the tflog subsystem used for HTTP traffic.
+	// Its level can be set independently of the provider using TF_LOG_PROVIDER_HTTP.
+	logSubsystem = "http"
+	// logLevelEnv is the environment variable which TF_LOG_PROVIDER_HTTP is named after.
+	logLevelEnv = "TF_LOG_PROVIDER"
+	// maxLoggedBodySize limits how much of a request or response body is logged or captured.
+	maxLoggedBodySize = 4 * 1024
+	redacted          = "***"
+)
+
+// sensitiveHeaders are never logged or captured, as they carry credentials.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// sensitiveFields matches JSON string fields carrying credentials, such as the password of a user or login request.
+// A regular expression is used, rather than decoding the body, so that truncated bodies are redacted too.
+var sensitiveFields = regexp.MustCompile(`("(?i:password|client_secret|token)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// loggingTransport logs every request sent to GraphDB, and its response, to the http tflog subsystem.
+// Credentials are redacted before anything is logged.
+type loggingTransport struct {
+	next    http.RoundTripper
+	capture *harCapture
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := tflog.NewSubsystem(req.Context(), logSubsystem, tflog.WithLevelFromEnv(logLevelEnv, logSubsystem))
+	started := time.Now()
+	// Bodies are only read when they are captured or logged, as they have to be buffered to be read
+	bodies := t.capture != nil || traceEnabled()
+	var reqBody string
+	if bodies {
+		reqBody = requestBody(req)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(started)
+
+	fields := map[string]any{
+		"method":     req.Method,
+		"url":        req.URL.Redacted(),
+		"latency_ms": elapsed.Milliseconds(),
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.SubsystemDebug(ctx, logSubsystem, "GraphDB request failed", fields)
+		t.record(ctx, req, reqBody, nil, "", started, elapsed, err)
+		return nil, err
+	}
+
+	fields["status"] = resp.StatusCode
+	tflog.SubsystemDebug(ctx, logSubsystem, "GraphDB request", fields)
+
+	if !bodies {
+		return resp, nil
+	}
+	respBody := peekResponseBody(resp)
+	tflog.SubsystemTrace(ctx, logSubsystem, "GraphDB request details", map[string]any{
+		"method":           req.Method,
+		"url":              req.URL.Redacted(),
+		"request_headers":  redactHeaders(req.Header),
+		"request_body":     reqBody,
+		"status":           resp.StatusCode,
+		"response_headers": redactHeaders(resp.Header),
+		"response_body":    respBody,
+	})
+	t.record(ctx, req, reqBody, resp, respBody, started, elapsed, nil)
+	return resp, nil
+}
+
+func (t *loggingTransport) record(ctx context.Context, req *http.Request, reqBody string, resp *http.Response, respBody string, started time.Time, elapsed time.Duration, err error) {
+	if t.capture == nil {
+		return
+	}
+	if err := t.capture.add(newHarEntry(req, reqBody, resp, respBody, started, elapsed, err)); err != nil {
+		tflog.SubsystemWarn(ctx, logSubsystem, "Failed to write HTTP capture file", map[string]any{"path": t.capture.path, "error": err.Error()})
+	}
+}
+
+// traceEnabled reports whether the http subsystem logs at trace level, where request and response bodies are logged.
+// Like tflog, its level is read from TF_LOG_PROVIDER_HTTP, falling back to the level of the provider logs.
+func traceEnabled() bool {
+	for _, env := range []string{logLevelEnv + "_" + strings.ToUpper(logSubsystem), logLevelEnv, "TF_LOG"} {
+		if level := hclog.LevelFromString(os.Getenv(env)); level != hclog.NoLevel {
+			return level == hclog.Trace
+		}
+	}
+	return false
+}
+
+// requestBody returns a redacted and truncated copy of the request body, without consuming it.
+// Uploads and other streamed bodies, which cannot be read twice, are not logged.
+func requestBody(req *http.Request) string {
+	if req.Body == nil || req.Body == http.NoBody {
+		return ""
+	}
+	if req.GetBody == nil || strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/") || req.Header.Get("Content-Encoding") != "" {
+		return "<streamed>"
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	return readLoggedBody(body)
+}
+
+// peekResponseBody returns a redacted and truncated copy of the start of the response body,
+// leaving the full body available to the caller.
+func peekResponseBody(resp *http.Response) string {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return ""
+	}
+	prefix, _ := io.ReadAll(io.LimitReader(resp.Body, maxLoggedBodySize+1))
+	resp.Body = &prefixedBody{Reader: io.MultiReader(bytes.NewReader(prefix), resp.Body), Closer: resp.Body}
+	return redactBody(prefix)
+}
+
+func readLoggedBody(r io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(r, maxLoggedBodySize+1))
+	return redactBody(data)
+}
+
+func redactBody(data []byte) string {
+	truncated := len(data) > maxLoggedBodySize
+	if truncated {
+		data = data[:maxLoggedBodySize]
+	}
+	body := sensitiveFields.ReplaceAllString(string(data), `$1"`+redacted+`"`)
+	if truncated {
+		body += "...(truncated)"
+	}
+	return body
+}
+
+func redactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			headers[name] = redacted
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// harCapture records HTTP traffic to a file in the HTTP Archive (HAR) format,
+// which can be attached to support tickets and opened in most browsers' developer tools.
+// The file is rewritten after every request, so it is complete even if the provider exits abruptly.
+type harCapture struct {
+	path string
+
+	mu  sync.Mutex
+	har har
+}
+
+func newHarCapture(path string) *harCapture {
+	return &harCapture{
+		path: path,
+		har: har{Log: harLog{
+			Version: "1.2",
+			Creator: harCreator{Name: "terraform-provider-graphdb"},
+			Entries: []harEntry{},
+		}},
+	}
+}
+
+func (h *harCapture) add(entry harEntry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.har.Log.Entries = append(h.har.Log.Entries, entry)
+	data, err := json.MarshalIndent(h.har, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(h.path, data, 0600)
+}
+
+type har struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            int64       `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	// Error is a custom field recording transport errors, which HAR has no standard representation for
+	Error string `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []harHeader `json:"headers"`
+	QueryString []harHeader `json:"queryString"`
+	PostData    *harPost    `json:"postData,omitempty"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Headers     []harHeader `json:"headers"`
+	Content     harContent  `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPost struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harTimings struct {
+	Send    int64 `json:"send"`
+	Wait    int64 `json:"wait"`
+	Receive int64 `json:"receive"`
+}
+
+func newHarEntry(req *http.Request, reqBody string, resp *http.Response, respBody string, started time.Time, elapsed time.Duration, err error) harEntry {
+	entry := harEntry{
+		StartedDateTime: started.UTC().Format(time.RFC3339Nano),
+		Time:            elapsed.Milliseconds(),
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         req.URL.Redacted(),
+			HTTPVersion: req.Proto,
+			Headers:     harHeaders(req.Header),
+			QueryString: []harHeader{},
+			HeadersSize: -1,
+			BodySize:    req.ContentLength,
+		},
+		Response: harResponse{
+			Headers:     []harHeader{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: harTimings{Wait: elapsed.Milliseconds()},
+	}
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			entry.Request.QueryString = append(entry.Request.QueryString, harHeader{Name: name, Value: value})
+		}
+	}
+	if reqBody != "" {
+		entry.Request.PostData = &harPost{MimeType: req.Header.Get("Content-Type"), Text: reqBody}
+	}
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+
+	entry.Response.Status = resp.StatusCode
+	entry.Response.StatusText = http.StatusText(resp.StatusCode)
+	entry.Response.HTTPVersion = resp.Proto
+	entry.Response.Headers = harHeaders(resp.Header)
+	entry.Response.BodySize = resp.ContentLength
+	entry.Response.Content = harContent{
+		Size:     resp.ContentLength,
+		MimeType: resp.Header.Get("Content-Type"),
+		Text:     respBody,
+	}
+	return entry
+}
+
+func harHeaders(header http.Header) []harHeader {
+	headers := []harHeader{}
+	for name, values := range header {
+		for _, value := range values {
+			if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+				value = redacted
+			}
+			headers = append(headers, harHeader{Name: name, Value: value})
+		}
+	}
+	return headers
+}
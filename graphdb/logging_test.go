@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func newLoggingTestServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/login":
+			w.Header().Set("Authorization", "GDB secret-token")
+			w.WriteHeader(http.StatusOK)
+		case "/rest/security/users/TestUser":
+			w.WriteHeader(http.StatusCreated)
+		default:
+			_, _ = w.Write([]byte(`[{"name": "repo"}]`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRedactBody(t *testing.T) {
+	cases := map[string]string{
+		`{"username": "admin", "password": "root"}`:       `{"username": "admin", "password": "***"}`,
+		`{"Password":"a \"quoted\" secret","x":1}`:        `{"Password":"***","x":1}`,
+		`{"client_secret": "abc", "token": "def"}`:        `{"client_secret": "***", "token": "***"}`,
+		`{"username": "admin", "grantedAuthorities": []}`: `{"username": "admin", "grantedAuthorities": []}`,
+	}
+	for body, want := range cases {
+		if got := redactBody([]byte(body)); got != want {
+			t.Fatalf("Unexpected redaction of %s. Wanted: %s. Got: %s", body, want, got)
+		}
+	}
+
+	long := redactBody(bytes.Repeat([]byte("a"), maxLoggedBodySize*2))
+	if !strings.HasSuffix(long, "...(truncated)") || len(long) > maxLoggedBodySize+20 {
+		t.Fatalf("Expected long body to be truncated. Got %d bytes", len(long))
+	}
+}
+
+func TestLoggingRedactsCredentials(t *testing.T) {
+	server := newLoggingTestServer(t)
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	t.Setenv("TF_LOG_PROVIDER_HTTP", "TRACE")
+
+	client := newTestClient(t, server.URL, WithTokenAuth("admin", "root"))
+	if err := client.CreateUser(ctx, User{Username: "TestUser", Password: "SuperSecret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := output.String()
+	if !strings.Contains(logs, "/rest/security/users/TestUser") {
+		t.Fatalf("Expected request to be logged. Got: %s", logs)
+	}
+	for _, secret := range []string{"SuperSecret", "secret-token", `\"root\"`} {
+		if strings.Contains(logs, secret) {
+			t.Fatalf("Expected %s to be redacted. Got: %s", secret, logs)
+		}
+	}
+}
+
+func TestLoggingRedactsURLCredentials(t *testing.T) {
+	server := newLoggingTestServer(t)
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	t.Setenv("TF_LOG_PROVIDER_HTTP", "TRACE")
+
+	client := newTestClient(t, strings.Replace(server.URL, "http://", "http://reader:SuperSecret@", 1))
+	if _, err := client.GetRepositories(ctx); err != nil {
+		t.Fatal(err)
+	}
+	logs := output.String()
+	if !strings.Contains(logs, "reader:xxxxx@") || strings.Contains(logs, "SuperSecret") {
+		t.Fatalf("Expected the password in the URL to be redacted. Got: %s", logs)
+	}
+}
+
+func TestLoggingOnlyReadsBodiesWhenTracing(t *testing.T) {
+	var bodies []io.ReadCloser
+	next := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		body := io.NopCloser(strings.NewReader(`[{"name": "repo"}]`))
+		bodies = append(bodies, body)
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: body, Request: req}, nil
+	})
+	transport := &loggingTransport{next: next}
+	send := func() *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "http://graphdb:7200/rest/repositories", nil)
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	t.Setenv("TF_LOG", "")
+	t.Setenv("TF_LOG_PROVIDER", "DEBUG")
+	t.Setenv("TF_LOG_PROVIDER_HTTP", "")
+	if resp := send(); resp.Body != bodies[0] {
+		t.Fatal("Expected the response body not to be buffered below trace level")
+	}
+	t.Setenv("TF_LOG_PROVIDER_HTTP", "TRACE")
+	if resp := send(); resp.Body == bodies[1] {
+		t.Fatal("Expected the response body to be buffered at trace level")
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestHTTPCapture(t *testing.T) {
+	server := newLoggingTestServer(t)
+	path := filepath.Join(t.TempDir(), "capture.har")
+
+	client := newTestClient(t, server.URL, WithTokenAuth("admin", "root"), WithHTTPCapture(path))
+	ctx := context.Background()
+	repos, err := client.GetRepositories(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The response body is still available to the client after being captured
+	if len(repos) != 1 || repos[0].Name != "repo" {
+		t.Fatalf("Unexpected repositories: %+v", repos)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var capture har
+	if err := json.Unmarshal(data, &capture); err != nil {
+		t.Fatal(err)
+	}
+	// The login, followed by the request itself
+	if len(capture.Log.Entries) != 2 {
+		t.Fatalf("Expected 2 entries. Got: %d", len(capture.Log.Entries))
+	}
+	entry := capture.Log.Entries[1]
+	if entry.Request.Method != http.MethodGet || entry.Response.Status != http.StatusOK || entry.Response.Content.Text != `[{"name": "repo"}]` {
+		t.Fatalf("Unexpected entry: %+v", entry)
+	}
+	for _, secret := range []string{"secret-token", `\"root\"`} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("Expected %s to be redacted from the capture", secret)
+		}
+	}
+}
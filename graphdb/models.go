@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// RepositorySummary is an entry in the list of repositories known to GraphDB.
+type RepositorySummary struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Uri         string `json:"uri"`
+	ExternalUrl string `json:"external_url"`
+	Type        string `json:"type"`
+	Local       bool   `json:"local"`
+	// State is whether the repository is running, one of the RepositoryState constants
+	State string `json:"state,omitempty"`
+}
+
+// States of a repository. GraphDB starts inactive repositories when they are first used.
+const (
+	RepositoryStateRunning    = "RUNNING"
+	RepositoryStateInactive   = "INACTIVE"
+	RepositoryStateStarting   = "STARTING"
+	RepositoryStateRestarting = "RESTARTING"
+	RepositoryStateStopping   = "STOPPING"
+)
+
+// Repository describes a single repository.
+type Repository struct {
+	ID         string                     `json:"id"`
+	Title      string                     `json:"title"`
+	Type       string                     `json:"type"`
+	SesameType string                     `json:"sesameType,omitempty"`
+	Location   string                     `json:"location"`
+	Params     map[string]RepositoryParam `json:"params,omitempty"`
+}
+
+// RepositorySize is the number of statements in a repository.
+type RepositorySize struct {
+	Total    int64 `json:"total"`
+	Explicit int64 `json:"explicit"`
+	Inferred int64 `json:"inferred"`
+}
+
+// RepositoryParam is a configuration parameter of a repository, such as the ruleset.
+type RepositoryParam struct {
+	Name  string `json:"name"`
+	Label string `json:"label,omitempty"`
+	Value string `json:"value"`
+	// Raw is the JSON value of a parameter which is not a string, such as the members of a FedX repository. When set,
+	// it is sent to GraphDB instead of Value, so that structured values are kept when a repository is updated.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON accepts parameter values which are not strings, as GraphDB returns lists and numbers for some
+// parameters. Lists are joined with commas, and other values are kept as their JSON text.
+func (p *RepositoryParam) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Name  string          `json:"name"`
+		Label string          `json:"label"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	p.Name, p.Label = raw.Name, raw.Label
+	p.Value = paramValue(raw.Value)
+	p.Raw = nil
+	if len(raw.Value) > 0 && raw.Value[0] != '"' && string(raw.Value) != "null" {
+		p.Raw = raw.Value
+	}
+	return nil
+}
+
+// MarshalJSON sends Raw as the value if it is set, and Value otherwise.
+func (p RepositoryParam) MarshalJSON() ([]byte, error) {
+	value := p.Raw
+	if value == nil {
+		var err error
+		if value, err = json.Marshal(p.Value); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(struct {
+		Name  string          `json:"name"`
+		Label string          `json:"label,omitempty"`
+		Value json.RawMessage `json:"value"`
+	}{p.Name, p.Label, value})
+}
+
+func paramValue(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil {
+		values := make([]string, 0, len(list))
+		for _, v := range list {
+			values = append(values, paramValue(v))
+		}
+		return strings.Join(values, ", ")
+	}
+	if string(raw) == "null" {
+		return ""
+	}
+	return string(raw)
+}
+
+// User is a GraphDB user account.
+// Password is only used when creating or updating a user, GraphDB never returns it.
+type User struct {
+	Username    string   `json:"username"`
+	Password    string   `json:"password"`
+	Authorities []string `json:"grantedAuthorities"`
+}
+
+// ImportResource is a file, directory or URL which can be imported into a repository,
+// along with the status of its most recent import.
+type ImportResource struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Format  string `json:"format,omitempty"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// ImportSettings controls how data is imported into a repository.
+type ImportSettings struct {
+	Name          string   `json:"name,omitempty"`
+	BaseURI       string   `json:"baseURI,omitempty"`
+	Context       string   `json:"context,omitempty"`
+	ReplaceGraphs []string `json:"replaceGraphs,omitempty"`
+	Format        string   `json:"format,omitempty"`
+	// Data is the URL to import from, when importing from a URL
+	Data string `json:"data,omitempty"`
+}
+
+// ServerImportRequest imports files from the GraphDB server's import directory.
+type ServerImportRequest struct {
+	FileNames      []string        `json:"fileNames"`
+	ImportSettings *ImportSettings `json:"importSettings,omitempty"`
+}
+
+// ClusterConfig is the configuration of a GraphDB cluster.
+type ClusterConfig struct {
+	Nodes                       []string `json:"nodes"`
+	ElectionMinTimeout          int64    `json:"electionMinTimeout"`
+	ElectionRangeTimeout        int64    `json:"electionRangeTimeout"`
+	HeartbeatInterval           int64    `json:"heartbeatInterval"`
+	MessageSizeKB               int64    `json:"messageSizeKB"`
+	VerificationTimeout         int64    `json:"verificationTimeout"`
+	TransactionLogMaximumSizeGB float64  `json:"transactionLogMaximumSizeGB"`
+	BatchUpdateInterval         int64    `json:"batchUpdateInterval"`
+}
+
+// ClusterNodeStatus is the status of a single node in a GraphDB cluster.
+type ClusterNodeStatus struct {
+	Address      string            `json:"address"`
+	NodeState    string            `json:"nodeState"`
+	Term         int64             `json:"term"`
+	SyncStatus   map[string]string `json:"syncStatus"`
+	LastLogTerm  int64             `json:"lastLogTerm"`
+	LastLogIndex int64             `json:"lastLogIndex"`
+	Endpoint     string            `json:"endpoint"`
+}
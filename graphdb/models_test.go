@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRepositoryParamUnmarshal(t *testing.T) {
+	var params map[string]RepositoryParam
+	err := json.Unmarshal([]byte(`{
+		"ruleset": {"name": "ruleset", "label": "Ruleset", "value": "owl-horst"},
+		"ftsIndexes": {"name": "ftsIndexes", "label": "FTS indexes", "value": ["default", "iri"]},
+		"queryTimeout": {"name": "queryTimeout", "label": "Query timeout", "value": 30},
+		"imports": {"name": "imports", "label": "Imported files", "value": null}
+	}`), &params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"ruleset": "owl-horst", "ftsIndexes": "default, iri", "queryTimeout": "30", "imports": ""}
+	for name, value := range want {
+		if params[name].Value != value {
+			t.Fatalf("Unexpected value for %s. Wanted: %q. Got: %q", name, value, params[name].Value)
+		}
+	}
+	if params["ruleset"].Label != "Ruleset" {
+		t.Fatalf("Unexpected label: %s", params["ruleset"].Label)
+	}
+}
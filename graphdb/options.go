@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// Option configures a Client.
+type Option func(c *Client) error
+
+// WithHTTPClient uses the given HTTP client to send requests.
+// It takes precedence over WithTLS, as the TLS configuration is part of the client's transport.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) error {
+		c.client = client
+		return nil
+	}
+}
+
+// WithTLS secures connections made over https using the given certificates.
+func WithTLS(config TLSConfig) Option {
+	return func(c *Client) error {
+		tc, err := config.build()
+		if err != nil {
+			return err
+		}
+		c.tlsConfig = tc
+		return nil
+	}
+}
+
+// WithBasicAuth sends the given credentials using HTTP basic authentication on every request.
+func WithBasicAuth(username string, password string) Option {
+	return func(c *Client) error {
+		c.auth = &basicAuthenticator{username: username, password: password}
+		return nil
+	}
+}
+
+// WithTokenAuth exchanges the given credentials for a GDB token, which is sent on every request
+// and refreshed whenever the server rejects it.
+func WithTokenAuth(username string, password string) Option {
+	return func(c *Client) error {
+		c.auth = &tokenAuthenticator{username: username, password: password}
+		return nil
+	}
+}
+
+// WithBearerToken sends the given static token as a bearer token on every request.
+func WithBearerToken(token string) Option {
+	return func(c *Client) error {
+		if token == "" {
+			return errors.New("Bearer token cannot be empty")
+		}
+		c.auth = &bearerAuthenticator{token: token}
+		return nil
+	}
+}
+
+// WithOAuth2 obtains bearer tokens from an OAuth2 token endpoint, using the client credentials flow.
+func WithOAuth2(config clientcredentials.Config) Option {
+	return func(c *Client) error {
+		if config.TokenURL == "" {
+			return errors.New("OAuth2 token URL cannot be empty")
+		}
+		c.auth = &oauth2Authenticator{config: config}
+		return nil
+	}
+}
+
+// WithHTTPCapture records every request and response to the file at path, in the HTTP Archive (HAR) format.
+// Credentials are redacted, but the capture may still contain sensitive data, such as repository contents.
+func WithHTTPCapture(path string) Option {
+	return func(c *Client) error {
+		if path == "" {
+			return errors.New("HTTP capture path cannot be empty")
+		}
+		c.capture = newHarCapture(path)
+		return nil
+	}
+}
+
+// WithUploadCompression compresses uploaded files, such as repository configurations, using gzip.
+// This reduces the size of large uploads, but requires GraphDB, or a proxy in front of it, to accept compressed requests.
+func WithUploadCompression() Option {
+	return func(c *Client) error {
+		c.compressUploads = true
+		return nil
+	}
+}
+
+// WithMaxConcurrentRequests limits how many requests are sent to GraphDB at once, across all goroutines using the client.
+// Further requests wait for one of the in-flight requests to complete. By default, there is no limit.
+func WithMaxConcurrentRequests(n int) Option {
+	return func(c *Client) error {
+		if n < 1 {
+			return errors.New("Max concurrent requests must be at least 1")
+		}
+		c.requests = make(semaphore, n)
+		return nil
+	}
+}
+
+// WithClusterEndpoints adds the other nodes of the GraphDB cluster the client's endpoint belongs to.
+// Mutating requests are sent to the cluster leader, which is discovered from the cluster status, and requests
+// fail over to another node when the one they were sent to cannot be reached.
+func WithClusterEndpoints(endpoints ...string) Option {
+	return func(c *Client) error {
+		nodes := []*url.URL{c.baseURL}
+		for _, endpoint := range endpoints {
+			node, err := ParseEndpoint(endpoint)
+			if err != nil {
+				return fmt.Errorf("Invalid GraphDB endpoint %s: %w", endpoint, err)
+			}
+			duplicate := false
+			for _, n := range nodes {
+				duplicate = duplicate || sameNode(n, node)
+			}
+			if !duplicate {
+				nodes = append(nodes, node)
+			}
+		}
+		if len(nodes) > 1 {
+			c.cluster = newClusterRouter(nodes)
+		}
+		return nil
+	}
+}
+
+// WithRetry sets how many times transient failures are retried and the maximum time to wait between attempts.
+func WithRetry(maxRetries int, maxWait time.Duration) Option {
+	return func(c *Client) error {
+		if maxRetries < 0 {
+			return errors.New("Max retries cannot be negative")
+		}
+		if maxWait <= 0 {
+			return errors.New("Retry max wait must be positive")
+		}
+		c.retry = newRetryPolicy(maxRetries, maxWait)
+		return nil
+	}
+}
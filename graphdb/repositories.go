@@ -0,0 +1,304 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RepositoriesAPI manages the repositories of a GraphDB instance.
+type RepositoriesAPI interface {
+	// GetRepositories lists all repositories.
+	GetRepositories(ctx context.Context) ([]RepositorySummary, error)
+	// GetRepository returns the repository with the given ID.
+	GetRepository(ctx context.Context, id string) (Repository, error)
+	// CreateRepository creates a repository from a configuration in Turtle syntax.
+	// The configuration is streamed to the server, and the request is only retried if config is an io.Seeker.
+	CreateRepository(ctx context.Context, config io.Reader) error
+	// CreateRepositoryJSON creates a repository from its configuration in the form returned by GetRepository,
+	// which is how repositories referring to uploaded files, such as Ontop repositories, are created.
+	CreateRepositoryJSON(ctx context.Context, repo Repository) error
+	// UploadRepositoryFile uploads a file used by a repository configuration, such as an Ontop mapping, to GraphDB.
+	// It returns the path of the file on the server, to be used as the value of the corresponding parameter.
+	UploadRepositoryFile(ctx context.Context, filename string, content io.Reader) (string, error)
+	// GetRepositoryConfig returns the live configuration of the repository with the given ID, in Turtle syntax.
+	GetRepositoryConfig(ctx context.Context, id string) (string, error)
+	// UpdateRepository changes the title and parameters of the repository with the given ID in place.
+	// GraphDB replaces the whole configuration, so repo should start from the one returned by GetRepository.
+	UpdateRepository(ctx context.Context, id string, repo Repository) error
+	// DeleteRepository deletes the repository with the given ID, along with all of its data.
+	DeleteRepository(ctx context.Context, id string) error
+	// GetRepositorySize returns the number of statements in the repository with the given ID.
+	GetRepositorySize(ctx context.Context, id string) (RepositorySize, error)
+	// GetRepositoryState returns whether the repository with the given ID is running, as one of the RepositoryState
+	// constants. GraphDB only reports it in the list of repositories.
+	GetRepositoryState(ctx context.Context, id string) (string, error)
+	// RestartRepository restarts the repository with the given ID, or starts it if it is inactive. It returns once
+	// GraphDB has accepted the request, and the repository may still be starting.
+	RestartRepository(ctx context.Context, id string) error
+	// ShutdownRepository stops the repository with the given ID, freeing its resources until it is used again.
+	ShutdownRepository(ctx context.Context, id string) error
+	// LockRepository serializes operations on the repository with the given ID, such as creating and then
+	// loading data into it, among the callers sharing the client. It waits until the repository is free and
+	// returns a function releasing it. The lock is local to the client and is not visible to GraphDB.
+	LockRepository(ctx context.Context, id string) (unlock func(), err error)
+	// InLocation returns the repositories of a remote location attached to GraphDB, such as another GraphDB
+	// instance, identified by its URL. The requests are still sent to the client's GraphDB, which forwards them.
+	// An empty location is the local GraphDB instance.
+	InLocation(location string) RepositoriesAPI
+}
+
+// locationRepositories are the repositories of a single location, the local one if location is empty.
+type locationRepositories struct {
+	c        *Client
+	location string
+}
+
+func (c *Client) InLocation(location string) RepositoriesAPI {
+	return locationRepositories{c: c, location: location}
+}
+
+func (c *Client) GetRepositories(ctx context.Context) ([]RepositorySummary, error) {
+	return c.InLocation("").GetRepositories(ctx)
+}
+
+func (c *Client) CreateRepository(ctx context.Context, config io.Reader) error {
+	return c.InLocation("").CreateRepository(ctx, config)
+}
+
+func (c *Client) CreateRepositoryJSON(ctx context.Context, repo Repository) error {
+	return c.InLocation("").CreateRepositoryJSON(ctx, repo)
+}
+
+func (c *Client) UploadRepositoryFile(ctx context.Context, filename string, content io.Reader) (string, error) {
+	return c.InLocation("").UploadRepositoryFile(ctx, filename, content)
+}
+
+func (c *Client) GetRepository(ctx context.Context, id string) (Repository, error) {
+	return c.InLocation("").GetRepository(ctx, id)
+}
+
+func (c *Client) GetRepositoryConfig(ctx context.Context, id string) (string, error) {
+	return c.InLocation("").GetRepositoryConfig(ctx, id)
+}
+
+func (c *Client) UpdateRepository(ctx context.Context, id string, repo Repository) error {
+	return c.InLocation("").UpdateRepository(ctx, id, repo)
+}
+
+func (c *Client) DeleteRepository(ctx context.Context, id string) error {
+	return c.InLocation("").DeleteRepository(ctx, id)
+}
+
+func (c *Client) GetRepositorySize(ctx context.Context, id string) (RepositorySize, error) {
+	return c.InLocation("").GetRepositorySize(ctx, id)
+}
+
+func (c *Client) GetRepositoryState(ctx context.Context, id string) (string, error) {
+	return c.InLocation("").GetRepositoryState(ctx, id)
+}
+
+func (c *Client) RestartRepository(ctx context.Context, id string) error {
+	return c.InLocation("").RestartRepository(ctx, id)
+}
+
+func (c *Client) ShutdownRepository(ctx context.Context, id string) error {
+	return c.InLocation("").ShutdownRepository(ctx, id)
+}
+
+func (c *Client) LockRepository(ctx context.Context, id string) (func(), error) {
+	return c.InLocation("").LockRepository(ctx, id)
+}
+
+func (l locationRepositories) InLocation(location string) RepositoriesAPI {
+	return l.c.InLocation(location)
+}
+
+// newRequest creates a request for a resource of the location.
+func (l locationRepositories) newRequest(ctx context.Context, method string, resource string, body any) (*http.Request, error) {
+	req, err := l.c.newRequest(ctx, method, resource, body)
+	if err != nil {
+		return nil, err
+	}
+	l.addLocation(req)
+	return req, nil
+}
+
+func (l locationRepositories) addLocation(req *http.Request) {
+	if l.location != "" {
+		req.URL.RawQuery = url.Values{"location": {l.location}}.Encode()
+	}
+}
+
+// describe names a repository in error messages, along with its location if it is not the local one.
+func (l locationRepositories) describe(id string) string {
+	if l.location == "" {
+		return id
+	}
+	return fmt.Sprintf("%s in location %s", id, l.location)
+}
+
+func (l locationRepositories) GetRepositories(ctx context.Context) ([]RepositorySummary, error) {
+	var data = []RepositorySummary{}
+
+	req, err := l.newRequest(ctx, http.MethodGet, "repositories", nil)
+	if err != nil {
+		return data, err
+	}
+	if err := l.c.do(req, &data, http.StatusOK); err != nil {
+		return data, fmt.Errorf("Failed to list repositories: %w", err)
+	}
+	return data, nil
+}
+
+func (l locationRepositories) CreateRepository(ctx context.Context, config io.Reader) error {
+	req, err := l.c.newUploadRequest(ctx, http.MethodPost, "repositories", "config", "upload", config)
+	if err != nil {
+		return err
+	}
+	l.addLocation(req)
+
+	if err := l.c.do(req, nil, http.StatusCreated); err != nil {
+		return fmt.Errorf("Failed to create repository: %w", err)
+	}
+	return nil
+}
+
+func (l locationRepositories) CreateRepositoryJSON(ctx context.Context, repo Repository) error {
+	req, err := l.newRequest(ctx, http.MethodPost, "repositories", repo)
+	if err != nil {
+		return err
+	}
+	if err := l.c.do(req, nil, http.StatusCreated); err != nil {
+		return fmt.Errorf("Failed to create repository %s: %w", l.describe(repo.ID), err)
+	}
+	return nil
+}
+
+func (l locationRepositories) UploadRepositoryFile(ctx context.Context, filename string, content io.Reader) (string, error) {
+	var path string
+
+	req, err := l.c.newUploadRequest(ctx, http.MethodPost, "repositories/uploadFile", "uploadFile", filename, content)
+	if err != nil {
+		return path, err
+	}
+	l.addLocation(req)
+	if err := l.c.do(req, &path, http.StatusOK); err != nil {
+		return path, fmt.Errorf("Failed to upload repository file %s: %w", filename, err)
+	}
+	return strings.TrimSpace(path), nil
+}
+
+func (l locationRepositories) GetRepository(ctx context.Context, id string) (Repository, error) {
+	var data = Repository{}
+
+	req, err := l.newRequest(ctx, http.MethodGet, "repositories/"+id, nil)
+	if err != nil {
+		return data, err
+	}
+	if err := l.c.do(req, &data, http.StatusOK); err != nil {
+		return data, fmt.Errorf("Failed to get repository %s: %w", l.describe(id), err)
+	}
+	return data, nil
+}
+
+func (l locationRepositories) GetRepositoryConfig(ctx context.Context, id string) (string, error) {
+	var config string
+
+	req, err := l.newRequest(ctx, http.MethodGet, "repositories/"+id+"/download-ttl", nil)
+	if err != nil {
+		return config, err
+	}
+	req.Header.Set("Accept", "text/turtle")
+	if err := l.c.do(req, &config, http.StatusOK); err != nil {
+		return config, fmt.Errorf("Failed to get configuration of repository %s: %w", l.describe(id), err)
+	}
+	return config, nil
+}
+
+func (l locationRepositories) UpdateRepository(ctx context.Context, id string, repo Repository) error {
+	req, err := l.newRequest(ctx, http.MethodPut, "repositories/"+id, repo)
+	if err != nil {
+		return err
+	}
+	if err := l.c.do(req, nil, http.StatusOK); err != nil {
+		return fmt.Errorf("Failed to update repository %s: %w", l.describe(id), err)
+	}
+	return nil
+}
+
+func (l locationRepositories) DeleteRepository(ctx context.Context, id string) error {
+	req, err := l.newRequest(ctx, http.MethodDelete, "repositories/"+id, nil)
+	if err != nil {
+		return err
+	}
+	if err := l.c.do(req, nil, http.StatusOK); err != nil {
+		return fmt.Errorf("Failed to delete repository %s: %w", l.describe(id), err)
+	}
+	return nil
+}
+
+func (l locationRepositories) GetRepositorySize(ctx context.Context, id string) (RepositorySize, error) {
+	var data RepositorySize
+
+	req, err := l.newRequest(ctx, http.MethodGet, "repositories/"+id+"/size", nil)
+	if err != nil {
+		return data, err
+	}
+	if err := l.c.do(req, &data, http.StatusOK); err != nil {
+		return data, fmt.Errorf("Failed to get size of repository %s: %w", l.describe(id), err)
+	}
+	return data, nil
+}
+
+func (l locationRepositories) GetRepositoryState(ctx context.Context, id string) (string, error) {
+	var data []RepositorySummary
+
+	req, err := l.newRequest(ctx, http.MethodGet, "repositories", nil)
+	if err != nil {
+		return "", err
+	}
+	if err := l.c.do(req, &data, http.StatusOK); err != nil {
+		return "", fmt.Errorf("Failed to get state of repository %s: %w", l.describe(id), err)
+	}
+	for _, repo := range data {
+		if repo.Name == id {
+			return repo.State, nil
+		}
+	}
+	return "", fmt.Errorf("Failed to get state of repository %s: %w", l.describe(id),
+		&APIError{StatusCode: http.StatusNotFound, Method: req.Method, URL: req.URL.String(), Message: "Repository not listed"})
+}
+
+func (l locationRepositories) RestartRepository(ctx context.Context, id string) error {
+	req, err := l.newRequest(ctx, http.MethodPost, "repositories/"+id+"/restart", nil)
+	if err != nil {
+		return err
+	}
+	if err := l.c.do(req, nil, http.StatusOK, http.StatusAccepted); err != nil {
+		return fmt.Errorf("Failed to restart repository %s: %w", l.describe(id), err)
+	}
+	return nil
+}
+
+func (l locationRepositories) ShutdownRepository(ctx context.Context, id string) error {
+	req, err := l.newRequest(ctx, http.MethodPost, "repositories/"+id+"/shutdown", nil)
+	if err != nil {
+		return err
+	}
+	if err := l.c.do(req, nil, http.StatusOK, http.StatusAccepted); err != nil {
+		return fmt.Errorf("Failed to shut down repository %s: %w", l.describe(id), err)
+	}
+	return nil
+}
+
+func (l locationRepositories) LockRepository(ctx context.Context, id string) (func(), error) {
+	// Repositories in different locations may have the same ID
+	return l.c.repositoryLocks.lock(ctx, l.location+"\x00"+id)
+}
@@ -0,0 +1,394 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nickrobison/terraform-provider-graphdb/internal/turtle"
+)
+
+// Namespaces of the repository configuration vocabulary.
+const (
+	NamespaceRDFS    = "http://www.w3.org/2000/01/rdf-schema#"
+	NamespaceRep     = "http://www.openrdf.org/config/repository#"
+	NamespaceSR      = "http://www.openrdf.org/config/repository/sail#"
+	NamespaceSail    = "http://www.openrdf.org/config/sail#"
+	NamespaceGraphDB = "http://www.ontotext.com/config/graphdb#"
+)
+
+// repositoryTypes maps the implementation types used in configuration files to the repository types reported by GraphDB.
+var repositoryTypes = map[string]string{
+	"graphdb:SailRepository":  "graphdb",
+	"openrdf:SailRepository":  "graphdb",
+	"graphdb:FedXRepository":  "fedx",
+	"graphdb:OntopRepository": "ontop",
+}
+
+// structuralPredicates link the nodes of a configuration together, rather than being parameters.
+var structuralPredicates = map[string]bool{
+	NamespaceRep + "repositoryType": true,
+	NamespaceSR + "sailImpl":        true,
+	NamespaceSail + "sailType":      true,
+	NamespaceSail + "delegate":      true,
+}
+
+// listParams are the parameters whose values are collections, written as comma separated lists in RepositoryParam.
+var listParams = map[string]bool{
+	"ftsIndexes": true,
+}
+
+// paramKeys maps the local names of the GraphDB parameters in configuration files to the keys of the same parameters
+// in the JSON form of a repository, as returned by GetRepository and sent by UpdateRepository. Parameters which are
+// not listed, such as those of FedX and Ontop repositories, have the same name in both.
+var paramKeys = map[string]string{
+	"base-URL":                                  "baseURL",
+	"entity-index-size":                         "entityIndexSize",
+	"entity-id-size":                            "entityIdSize",
+	"disable-sameAs":                            "disableSameAs",
+	"check-for-inconsistencies":                 "checkForInconsistencies",
+	"query-timeout":                             "queryTimeout",
+	"query-limit-results":                       "queryLimitResults",
+	"read-only":                                 "readOnly",
+	"enable-context-index":                      "enableContextIndex",
+	"enable-literal-index":                      "enableLiteralIndex",
+	"enable-fts-index":                          "enableFtsIndex",
+	"enable-predicate-list":                     "enablePredicateList",
+	"fts-indexes":                               "ftsIndexes",
+	"fts-string-literals-index":                 "ftsStringLiteralsIndex",
+	"fts-iris-index":                            "ftsIrisIndex",
+	"in-memory-literal-properties":              "inMemoryLiteralProperties",
+	"storage-folder":                            "storageFolder",
+	"repository-type":                           "repositoryType",
+	"throw-QueryEvaluationException-on-timeout": "throwQueryEvaluationExceptionOnTimeout",
+}
+
+// paramLocalNames is the inverse of paramKeys.
+var paramLocalNames = func() map[string]string {
+	names := make(map[string]string, len(paramKeys))
+	for name, key := range paramKeys {
+		names[key] = name
+	}
+	return names
+}()
+
+// ParamKey returns the key in the JSON form of a repository of the parameter with the given local name in
+// configuration files, e.g. entityIdSize for entity-id-size. Keys are returned unchanged.
+func ParamKey(name string) string {
+	if key, ok := paramKeys[name]; ok {
+		return key
+	}
+	return name
+}
+
+// ParamLocalName returns the local name in configuration files of the parameter with the given key in the JSON form
+// of a repository. It is the inverse of ParamKey.
+func ParamLocalName(key string) string {
+	if name, ok := paramLocalNames[key]; ok {
+		return name
+	}
+	return key
+}
+
+// FormatRepositoryConfig renders the configuration of a graphdb:SailRepository in Turtle syntax, with the ID, title and
+// parameters of repo. It is the inverse of ParseRepositoryConfig, and parameters are written in order of their keys.
+func FormatRepositoryConfig(repo Repository) string {
+	var b strings.Builder
+	b.WriteString("@prefix rdfs: <" + NamespaceRDFS + "> .\n")
+	b.WriteString("@prefix rep: <" + NamespaceRep + "> .\n")
+	b.WriteString("@prefix sr: <" + NamespaceSR + "> .\n")
+	b.WriteString("@prefix sail: <" + NamespaceSail + "> .\n")
+	b.WriteString("@prefix graphdb: <" + NamespaceGraphDB + "> .\n\n")
+
+	b.WriteString("[] a rep:Repository ;\n")
+	fmt.Fprintf(&b, "    rep:repositoryID %s ;\n", formatLiteral(repo.ID))
+	fmt.Fprintf(&b, "    rdfs:label %s ;\n", formatLiteral(repo.Title))
+	b.WriteString("    rep:repositoryImpl [\n")
+	b.WriteString("        rep:repositoryType \"graphdb:SailRepository\" ;\n")
+	b.WriteString("        sr:sailImpl [\n")
+	b.WriteString("            sail:sailType \"graphdb:Sail\"")
+
+	names := make([]string, 0, len(repo.Params))
+	for name := range repo.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := repo.Params[name].Value
+		fmt.Fprintf(&b, " ;\n            graphdb:%s ", ParamLocalName(name))
+		if !listParams[ParamKey(name)] {
+			b.WriteString(formatLiteral(value))
+			continue
+		}
+		b.WriteString("(")
+		for i, member := range splitList(value) {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(formatLiteral(member))
+		}
+		b.WriteString(")")
+	}
+	b.WriteString("\n        ]\n    ] .\n")
+	return b.String()
+}
+
+// literalEscaper escapes the characters which cannot appear as-is in a quoted Turtle string.
+var literalEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// formatLiteral quotes value as a Turtle string literal.
+func formatLiteral(value string) string {
+	return `"` + literalEscaper.Replace(value) + `"`
+}
+
+func splitList(value string) []string {
+	var members []string
+	for _, member := range strings.Split(value, ",") {
+		if member = strings.TrimSpace(member); member != "" {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// ParseRepositoryConfig extracts the ID, title, type and parameters of a repository from its configuration in
+// Turtle syntax, in the same form as GraphDB returns them. Parameters are keyed and named by ParamKey of the local
+// name of their predicate, e.g. queryTimeout for graphdb:query-timeout, and collections are joined with commas.
+func ParseRepositoryConfig(config string) (Repository, error) {
+	g, err := turtle.Parse(config)
+	if err != nil {
+		return Repository{}, fmt.Errorf("Invalid repository configuration: %w", err)
+	}
+	return repositoryFromGraph(g)
+}
+
+// RepositoryConfigDrift compares the live configuration of a repository with the configured one, returning the names
+// of the settings which differ: "type", or the names of parameters. Only the parameters set by the configured
+// configuration are compared, as GraphDB fills in defaults for the others, and the label is not compared, as it can be
+// overridden. Differences in formatting, prefixes and statement order are ignored.
+func RepositoryConfigDrift(configured string, live string) ([]string, error) {
+	configuredGraph, err := turtle.Parse(configured)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid repository configuration: %w", err)
+	}
+	liveGraph, err := turtle.Parse(live)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid live repository configuration: %w", err)
+	}
+	if reflect.DeepEqual(configuredGraph.Canonical(), liveGraph.Canonical()) {
+		return nil, nil
+	}
+
+	want, err := repositoryFromGraph(configuredGraph)
+	if err != nil {
+		return nil, err
+	}
+	got, err := repositoryFromGraph(liveGraph)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid live repository configuration: %w", err)
+	}
+
+	var drift []string
+	if want.SesameType != got.SesameType {
+		drift = append(drift, "type")
+	}
+	names := make([]string, 0, len(want.Params))
+	for name := range want.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if param, ok := got.Params[name]; !ok || param.Value != want.Params[name].Value {
+			drift = append(drift, name)
+		}
+	}
+	return drift, nil
+}
+
+// ParseRepositoryJSON extracts the repository described by a configuration in the JSON form GraphDB accepts when
+// creating a repository, which is the one GetRepository returns. Parameters are named after their key if they do not
+// set a name.
+func ParseRepositoryJSON(config string) (Repository, error) {
+	var repo Repository
+	if err := json.Unmarshal([]byte(config), &repo); err != nil {
+		return repo, fmt.Errorf("Invalid repository configuration: %w", err)
+	}
+	if repo.ID == "" {
+		return repo, errors.New("Invalid repository configuration: no id found")
+	}
+	for name, param := range repo.Params {
+		if param.Name == "" {
+			param.Name = name
+			repo.Params[name] = param
+		}
+	}
+	return repo, nil
+}
+
+// RefreshRepositoryJSON compares a repository returned by GraphDB with its configured configuration in JSON form.
+// It returns the names of the settings which differ, "type", "sesameType" or the names of parameters, and the
+// configuration with their values replaced by the live ones. Like RepositoryConfigDrift, only the settings present in
+// the configuration are compared, and the title is not. The configuration is returned unchanged if there is no drift.
+func RefreshRepositoryJSON(configured string, live Repository) (string, []string, error) {
+	want, err := ParseRepositoryJSON(configured)
+	if err != nil {
+		return configured, nil, err
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(configured), &doc); err != nil {
+		return configured, nil, fmt.Errorf("Invalid repository configuration: %w", err)
+	}
+	var params map[string]map[string]json.RawMessage
+	if raw, ok := doc["params"]; ok {
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return configured, nil, fmt.Errorf("Invalid repository configuration: %w", err)
+		}
+	}
+
+	var drift []string
+	set := func(values map[string]json.RawMessage, key string, value any) error {
+		raw, err := json.Marshal(value)
+		values[key] = raw
+		return err
+	}
+	if want.Type != "" && want.Type != live.Type {
+		drift = append(drift, "type")
+		if err := set(doc, "type", live.Type); err != nil {
+			return configured, nil, err
+		}
+	}
+	if want.SesameType != "" && want.SesameType != live.SesameType {
+		drift = append(drift, "sesameType")
+		if err := set(doc, "sesameType", live.SesameType); err != nil {
+			return configured, nil, err
+		}
+	}
+
+	names := make([]string, 0, len(want.Params))
+	for name := range want.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		param, ok := live.Params[name]
+		if ok && param.Value == want.Params[name].Value {
+			continue
+		}
+		drift = append(drift, name)
+		if !ok {
+			delete(params, name)
+			continue
+		}
+		var value any = param.Value
+		if param.Raw != nil {
+			value = param.Raw
+		}
+		if params[name] == nil {
+			params[name] = map[string]json.RawMessage{}
+		}
+		if err := set(params[name], "value", value); err != nil {
+			return configured, nil, err
+		}
+	}
+	if len(drift) == 0 {
+		return configured, nil, nil
+	}
+	if params != nil {
+		if err := set(doc, "params", params); err != nil {
+			return configured, nil, err
+		}
+	}
+
+	refreshed, err := json.Marshal(doc)
+	if err != nil {
+		return configured, nil, err
+	}
+	return string(refreshed), drift, nil
+}
+
+func repositoryFromGraph(g *turtle.Graph) (Repository, error) {
+	var repo Repository
+
+	node, ok := repositoryNode(g)
+	if !ok {
+		return repo, errors.New("Invalid repository configuration: no rep:repositoryID found")
+	}
+	if id, ok := g.Object(node, NamespaceRep+"repositoryID"); ok {
+		repo.ID = id.Value
+	}
+	if label, ok := g.Object(node, NamespaceRDFS+"label"); ok {
+		repo.Title = label.Value
+	}
+
+	impl, ok := g.Object(node, NamespaceRep+"repositoryImpl")
+	if !ok {
+		return repo, nil
+	}
+	if t, ok := g.Object(impl, NamespaceRep+"repositoryType"); ok {
+		repo.SesameType = t.Value
+		repo.Type = t.Value
+		if mapped, ok := repositoryTypes[t.Value]; ok {
+			repo.Type = mapped
+		}
+	}
+
+	repo.Params = map[string]RepositoryParam{}
+	addParams(g, impl, repo.Params)
+	if sail, ok := g.Object(impl, NamespaceSR+"sailImpl"); ok {
+		addParams(g, sail, repo.Params)
+	}
+	return repo, nil
+}
+
+// repositoryNode returns the node describing the repository, which is usually, but not necessarily, typed as a rep:Repository.
+func repositoryNode(g *turtle.Graph) (turtle.Term, bool) {
+	if nodes := g.Subjects(turtle.RDF+"type", turtle.NewIRI(NamespaceRep+"Repository")); len(nodes) > 0 {
+		return nodes[0], true
+	}
+	for _, t := range g.Triples {
+		if t.Predicate.Value == NamespaceRep+"repositoryID" {
+			return t.Subject, true
+		}
+	}
+	return turtle.Term{}, false
+}
+
+func addParams(g *turtle.Graph, node turtle.Term, params map[string]RepositoryParam) {
+	for _, t := range g.Triples {
+		if t.Subject != node || structuralPredicates[t.Predicate.Value] || t.Object.Kind == turtle.BlankNode && !isList(g, t.Object) {
+			continue
+		}
+		name := ParamKey(localName(t.Predicate.Value))
+		params[name] = RepositoryParam{Name: name, Value: termValue(g, t.Object)}
+	}
+}
+
+func isList(g *turtle.Graph, node turtle.Term) bool {
+	_, ok := g.List(node)
+	return ok
+}
+
+func termValue(g *turtle.Graph, term turtle.Term) string {
+	members, ok := g.List(term)
+	if !ok || term.Kind == turtle.Literal {
+		return term.Value
+	}
+	values := make([]string, 0, len(members))
+	for _, m := range members {
+		values = append(values, m.Value)
+	}
+	return strings.Join(values, ", ")
+}
+
+func localName(iri string) string {
+	if i := strings.LastIndexAny(iri, "#/:"); i >= 0 {
+		return iri[i+1:]
+	}
+	return iri
+}
@@ -0,0 +1,285 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nickrobison/terraform-provider-graphdb/internal/turtle"
+)
+
+func TestParseRepositoryConfig(t *testing.T) {
+	repo, err := ParseRepositoryConfig(`
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#>.
+@prefix rep: <http://www.openrdf.org/config/repository#>.
+@prefix sail: <http://www.openrdf.org/config/sail#>.
+@prefix sr: <http://www.openrdf.org/config/repository/sail#>.
+@prefix graphdb: <http://www.ontotext.com/config/graphdb#>.
+
+[] a rep:Repository ;
+    rep:repositoryID "TestRepo" ;
+    rdfs:label "Test repository" ;
+    rep:repositoryImpl [
+        rep:repositoryType "graphdb:SailRepository" ;
+        sr:sailImpl [
+            sail:sailType "graphdb:Sail" ;
+            graphdb:ruleset "rdfsplus-optimized" ;
+            graphdb:query-timeout 30 ;
+            graphdb:fts-indexes ("default" "iri")
+        ]
+    ].
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Repository{
+		ID:         "TestRepo",
+		Title:      "Test repository",
+		Type:       "graphdb",
+		SesameType: "graphdb:SailRepository",
+		Params: map[string]RepositoryParam{
+			"ruleset":      {Name: "ruleset", Value: "rdfsplus-optimized"},
+			"queryTimeout": {Name: "queryTimeout", Value: "30"},
+			"ftsIndexes":   {Name: "ftsIndexes", Value: "default, iri"},
+		},
+	}
+	if !reflect.DeepEqual(repo, want) {
+		t.Fatalf("Unexpected repository. Wanted: %+v. Got: %+v", want, repo)
+	}
+
+	var syntaxErr *turtle.SyntaxError
+	if _, err := ParseRepositoryConfig(`[] rep:repositoryID "TestRepo" .`); !errors.As(err, &syntaxErr) || syntaxErr.Line != 1 {
+		t.Fatalf("Expected a syntax error on line 1. Got: %v", err)
+	}
+	if _, err := ParseRepositoryConfig(`<urn:a> <urn:b> <urn:c> .`); err == nil {
+		t.Fatal("Expected a configuration without a repository ID to be invalid")
+	}
+}
+
+// TestParseRepositoryConfigKeys checks that the parameters of a configuration file have the keys of the repository
+// returned by GraphDB, so that they can be compared with it and merged into it.
+func TestParseRepositoryConfigKeys(t *testing.T) {
+	var live Repository
+	err := json.Unmarshal([]byte(`{
+		"id": "TestRepo",
+		"title": "Test repository",
+		"type": "graphdb",
+		"sesameType": "graphdb:SailRepository",
+		"location": "",
+		"params": {
+			"ruleset": {"name": "ruleset", "label": "Ruleset", "value": "rdfsplus-optimized"},
+			"queryTimeout": {"name": "queryTimeout", "label": "Query timeout (seconds)", "value": "30"},
+			"entityIdSize": {"name": "entityIdSize", "label": "Entity ID size", "value": "32"},
+			"disableSameAs": {"name": "disableSameAs", "label": "Disable owl:sameAs", "value": "true"},
+			"enableContextIndex": {"name": "enableContextIndex", "label": "Enable context index", "value": "false"},
+			"storageFolder": {"name": "storageFolder", "label": "Storage folder", "value": "storage"},
+			"baseURL": {"name": "baseURL", "label": "Base URL", "value": "http://example.org/owlim#"}
+		}
+	}`), &live)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseRepositoryConfig(FormatRepositoryConfig(Repository{
+		ID: "TestRepo",
+		Params: map[string]RepositoryParam{
+			"query-timeout":        {Value: "30"},
+			"entity-id-size":       {Value: "32"},
+			"disable-sameAs":       {Value: "true"},
+			"enable-context-index": {Value: "false"},
+			"storage-folder":       {Value: "storage"},
+			"base-URL":             {Value: "http://example.org/owlim#"},
+		},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, param := range parsed.Params {
+		current, ok := live.Params[key]
+		if !ok || current.Name != param.Name || current.Value != param.Value {
+			t.Fatalf("Parameter %s does not match the live repository. Wanted: %+v. Got: %+v", key, current, param)
+		}
+		if name := ParamLocalName(key); ParamKey(name) != key {
+			t.Fatalf("Unexpected local name for %s: %s", key, name)
+		}
+	}
+	if len(parsed.Params) != 6 {
+		t.Fatalf("Unexpected parameters: %+v", parsed.Params)
+	}
+}
+
+func TestFormatRepositoryConfig(t *testing.T) {
+	config := FormatRepositoryConfig(Repository{
+		ID:    "TestRepo",
+		Title: `A "quoted" title`,
+		Params: map[string]RepositoryParam{
+			"ruleset":      {Name: "ruleset", Value: "owl-horst"},
+			"readOnly":     {Name: "readOnly", Value: "true"},
+			"ftsIndexes":   {Name: "ftsIndexes", Value: "default, iri"},
+			"entityIdSize": {Name: "entityIdSize", Value: "32"},
+		},
+	})
+
+	for _, want := range []string{
+		`rep:repositoryID "TestRepo" ;`,
+		`rdfs:label "A \"quoted\" title" ;`,
+		`rep:repositoryType "graphdb:SailRepository" ;`,
+		// Parameters are written by their local names, in order of their keys
+		`graphdb:entity-id-size "32" ;
+            graphdb:fts-indexes ("default" "iri") ;
+            graphdb:read-only "true" ;
+            graphdb:ruleset "owl-horst"
+`,
+	} {
+		if !strings.Contains(config, want) {
+			t.Fatalf("Expected configuration to contain %s. Got:\n%s", want, config)
+		}
+	}
+}
+
+func TestFormatRepositoryConfigRoundTrip(t *testing.T) {
+	repo := Repository{
+		ID:         "TestRepo",
+		Title:      `A "quoted" title`,
+		Type:       "graphdb",
+		SesameType: "graphdb:SailRepository",
+		Params: map[string]RepositoryParam{
+			"ruleset":    {Name: "ruleset", Value: "owl-horst"},
+			"readOnly":   {Name: "readOnly", Value: "true"},
+			"ftsIndexes": {Name: "ftsIndexes", Value: "default, iri"},
+		},
+	}
+
+	parsed, err := ParseRepositoryConfig(FormatRepositoryConfig(repo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, repo) {
+		t.Fatalf("Unexpected repository after formatting and parsing. Wanted: %+v. Got: %+v", repo, parsed)
+	}
+}
+
+func TestParamKey(t *testing.T) {
+	for name, key := range map[string]string{
+		"entity-id-size": "entityIdSize",
+		"base-URL":       "baseURL",
+		"ruleset":        "ruleset",
+		"member":         "member",
+	} {
+		if got := ParamKey(name); got != key {
+			t.Fatalf("Unexpected key for %s. Wanted: %s. Got: %s", name, key, got)
+		}
+		if got := ParamLocalName(key); got != name {
+			t.Fatalf("Unexpected local name for %s. Wanted: %s. Got: %s", key, name, got)
+		}
+	}
+}
+
+func TestRepositoryConfigDrift(t *testing.T) {
+	configured := `
+@prefix rep: <http://www.openrdf.org/config/repository#>.
+@prefix sr: <http://www.openrdf.org/config/repository/sail#>.
+@prefix graphdb: <http://www.ontotext.com/config/graphdb#>.
+
+[] a rep:Repository ;
+    rep:repositoryID "TestRepo" ;
+    rep:repositoryImpl [
+        rep:repositoryType "graphdb:SailRepository" ;
+        sr:sailImpl [ graphdb:ruleset "owl-horst" ; graphdb:query-timeout "30" ]
+    ].
+`
+	live := func(ruleset string) string {
+		return FormatRepositoryConfig(Repository{
+			ID:    "TestRepo",
+			Title: "Renamed",
+			Params: map[string]RepositoryParam{
+				"ruleset":      {Value: ruleset},
+				"queryTimeout": {Value: "30"},
+				"readOnly":     {Value: "false"},
+			},
+		})
+	}
+
+	drift, err := RepositoryConfigDrift(configured, live("owl-horst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drift) != 0 {
+		t.Fatalf("Expected no drift for defaults and labels. Got: %v", drift)
+	}
+
+	drift, err = RepositoryConfigDrift(configured, live("rdfs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(drift, []string{"ruleset"}) {
+		t.Fatalf("Unexpected drift: %v", drift)
+	}
+}
+
+func TestParseRepositoryJSON(t *testing.T) {
+	repo, err := ParseRepositoryJSON(`{"id": "TestRepo", "type": "graphdb", "params": {"ruleset": {"value": "owl-horst"}, "queryTimeout": {"name": "queryTimeout", "value": 30}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]RepositoryParam{
+		"ruleset":      {Name: "ruleset", Value: "owl-horst"},
+		"queryTimeout": {Name: "queryTimeout", Value: "30", Raw: json.RawMessage("30")},
+	}
+	if repo.ID != "TestRepo" || repo.Type != "graphdb" || !reflect.DeepEqual(repo.Params, want) {
+		t.Fatalf("Unexpected repository: %+v", repo)
+	}
+
+	if _, err := ParseRepositoryJSON(`{"title": "Missing ID"}`); err == nil {
+		t.Fatal("Expected a configuration without an id to be invalid")
+	}
+	if _, err := ParseRepositoryJSON(`{"id": `); err == nil {
+		t.Fatal("Expected invalid JSON to be rejected")
+	}
+}
+
+func TestRefreshRepositoryJSON(t *testing.T) {
+	configured := `{
+  "id": "TestRepo",
+  "type": "graphdb",
+  "params": {"ruleset": {"name": "ruleset", "value": "owl-horst"}, "queryTimeout": {"value": "30"}}
+}`
+	live := func(ruleset string) Repository {
+		return Repository{
+			ID:    "TestRepo",
+			Title: "Renamed",
+			Type:  "graphdb",
+			Params: map[string]RepositoryParam{
+				"ruleset":      {Name: "ruleset", Label: "Ruleset", Value: ruleset},
+				"queryTimeout": {Name: "queryTimeout", Label: "Query timeout", Value: "30"},
+				"readOnly":     {Name: "readOnly", Value: "false"},
+			},
+		}
+	}
+
+	refreshed, drift, err := RefreshRepositoryJSON(configured, live("owl-horst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drift) != 0 || refreshed != configured {
+		t.Fatalf("Expected no drift for defaults, labels and the title. Got: %v, %s", drift, refreshed)
+	}
+
+	refreshed, drift, err = RefreshRepositoryJSON(configured, live("rdfs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(drift, []string{"ruleset"}) {
+		t.Fatalf("Unexpected drift: %v", drift)
+	}
+	want := `{"id":"TestRepo","params":{"queryTimeout":{"value":"30"},"ruleset":{"name":"ruleset","value":"rdfs"}},"type":"graphdb"}`
+	if refreshed != want {
+		t.Fatalf("Unexpected refreshed configuration. Wanted: %s. Got: %s", want, refreshed)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultMaxRetries   = 3
+	DefaultRetryMaxWait = 30 * time.Second
+	retryMinWait        = 500 * time.Millisecond
+)
+
+// retryPolicy decides whether a failed request should be retried and how long to wait before doing so.
+type retryPolicy struct {
+	maxRetries int
+	minWait    time.Duration
+	maxWait    time.Duration
+}
+
+func newRetryPolicy(maxRetries int, maxWait time.Duration) retryPolicy {
+	minWait := retryMinWait
+	if maxWait < minWait {
+		minWait = maxWait
+	}
+	return retryPolicy{maxRetries: maxRetries, minWait: minWait, maxWait: maxWait}
+}
+
+// shouldRetry classifies the outcome of an attempt.
+// Idempotent requests are retried on any transient failure, while non-idempotent ones (e.g. POST) are only
+// retried when the server cannot have processed them, such as when the connection was refused.
+func (p retryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error, attempt int) bool {
+	if attempt >= p.maxRetries || req.Context().Err() != nil {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body has already been consumed and cannot be sent again
+		return false
+	}
+
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+		return isIdempotent(req.Method) && isTransientError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(req.Method)
+	}
+	return false
+}
+
+// backoff returns the time to wait before the next attempt, using exponential backoff with jitter.
+// A Retry-After header sent by the server takes precedence, but is still capped at the maximum wait.
+func (p retryPolicy) backoff(resp *http.Response, attempt int) time.Duration {
+	if resp != nil {
+		if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s >= 0 {
+			return minDuration(time.Duration(s)*time.Second, p.maxWait)
+		}
+	}
+
+	wait := p.minWait << attempt
+	if wait <= 0 || wait > p.maxWait {
+		wait = p.maxWait
+	}
+	// Wait at least half of the computed time, so that concurrent clients spread out
+	half := wait / 2
+	// #nosec G404 -- Jitter does not need a cryptographically secure source
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isTransientError reports whether a transport error is likely to succeed if retried,
+// e.g. a dropped connection, as opposed to a permanent failure such as an untrusted certificate.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	// url.Error implements net.Error itself, so look for the underlying network failure instead
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var netErr net.Error
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return errors.As(urlErr.Err, &netErr) && netErr.Timeout()
+	}
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryTransport applies a retry policy to requests which are not sent by the Client itself, such as the token
+// requests of the OAuth2 client credentials flow.
+type retryTransport struct {
+	next   http.RoundTripper
+	policy retryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := next.RoundTrip(req)
+		if !t.policy.shouldRetry(req, resp, err, attempt) {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(req.Context(), t.policy.backoff(resp, attempt)); err != nil {
+			return nil, err
+		}
+		if req, err = rewindRequest(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// rewindRequest returns a copy of req with a fresh body, so that it can be sent again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+	return retry, nil
+}
+
+// sleep waits for the given duration, returning early with an error if the context is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func minDuration(a time.Duration, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newFlakyServer returns a server which answers with 503 for the first failures requests.
+func newFlakyServer(t *testing.T, failures int) (*httptest.Server, *int) {
+	t.Helper()
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &attempts
+}
+
+func TestRetryIdempotentRequest(t *testing.T) {
+	server, attempts := newFlakyServer(t, 2)
+	client := newTestClient(t, server.URL, WithRetry(3, time.Millisecond))
+
+	if _, err := client.GetRepositories(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if *attempts != 3 {
+		t.Fatalf("Expected 3 attempts. Got: %d", *attempts)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	server, attempts := newFlakyServer(t, 10)
+	client := newTestClient(t, server.URL, WithRetry(2, time.Millisecond))
+
+	if err := client.DeleteRepository(context.Background(), "repo"); err == nil {
+		t.Fatal("Expected request to fail once retries are exhausted")
+	}
+	if *attempts != 3 {
+		t.Fatalf("Expected 3 attempts. Got: %d", *attempts)
+	}
+}
+
+func TestRetryNonIdempotentRequest(t *testing.T) {
+	server, attempts := newFlakyServer(t, 1)
+	client := newTestClient(t, server.URL, WithRetry(3, time.Millisecond))
+
+	if err := client.CreateRepository(context.Background(), strings.NewReader("config")); err == nil {
+		t.Fatal("Expected POST to fail without being retried")
+	}
+	if *attempts != 1 {
+		t.Fatalf("Expected a single attempt. Got: %d", *attempts)
+	}
+}
+
+func TestRetryConnectionRefused(t *testing.T) {
+	server, _ := newFlakyServer(t, 0)
+	client := newTestClient(t, server.URL, WithRetry(2, time.Millisecond))
+	server.Close()
+
+	start := time.Now()
+	if err := client.CreateRepository(context.Background(), strings.NewReader("config")); err == nil {
+		t.Fatal("Expected request to a closed server to fail")
+	}
+	if time.Since(start) < time.Millisecond {
+		t.Fatal("Expected the refused connection to be retried with a backoff")
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := newRetryPolicy(5, 4*time.Second)
+	for attempt := 0; attempt < 10; attempt++ {
+		wait := policy.backoff(nil, attempt)
+		if wait < retryMinWait/2 || wait > 4*time.Second {
+			t.Fatalf("Backoff for attempt %d out of bounds: %s", attempt, wait)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"120"}}}
+	if wait := policy.backoff(resp, 0); wait != 4*time.Second {
+		t.Fatalf("Expected Retry-After to be capped at the max wait. Got: %s", wait)
+	}
+}
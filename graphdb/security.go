@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// SecurityAPI manages GraphDB users.
+type SecurityAPI interface {
+	// GetUsers lists all users.
+	GetUsers(ctx context.Context) ([]User, error)
+	// GetUser returns the user with the given username.
+	GetUser(ctx context.Context, username string) (User, error)
+	// CreateUser creates a new user.
+	CreateUser(ctx context.Context, user User) error
+	// UpdateUser replaces the password and authorities of an existing user.
+	UpdateUser(ctx context.Context, username string, user User) error
+	// DeleteUser deletes the user with the given username.
+	DeleteUser(ctx context.Context, username string) error
+	// SecurityEnabled reports whether security is enabled, i.e. whether requests must be authenticated.
+	SecurityEnabled(ctx context.Context) (bool, error)
+	// GetAuthenticatedUser returns the user the client's credentials belong to.
+	GetAuthenticatedUser(ctx context.Context) (User, error)
+}
+
+func (c *Client) GetUsers(ctx context.Context) ([]User, error) {
+	var users []User
+
+	req, err := c.newRequest(ctx, http.MethodGet, c.usersResource(""), nil)
+	if err != nil {
+		return users, err
+	}
+	if err := c.do(req, &users, http.StatusOK); err != nil {
+		return users, fmt.Errorf("Failed to list users: %w", err)
+	}
+	return users, nil
+}
+
+func (c *Client) GetUser(ctx context.Context, username string) (User, error) {
+	var user User
+
+	req, err := c.newRequest(ctx, http.MethodGet, c.usersResource(username), nil)
+	if err != nil {
+		return user, err
+	}
+	if err := c.do(req, &user, http.StatusOK); err != nil {
+		return user, fmt.Errorf("Failed to get user %s: %w", username, err)
+	}
+	return user, nil
+}
+
+func (c *Client) CreateUser(ctx context.Context, user User) error {
+	req, err := c.newRequest(ctx, http.MethodPost, c.usersResource(user.Username), user)
+	if err != nil {
+		return err
+	}
+	if err := c.do(req, nil, http.StatusCreated); err != nil {
+		return fmt.Errorf("Failed to create user %s: %w", user.Username, err)
+	}
+	return nil
+}
+
+func (c *Client) UpdateUser(ctx context.Context, username string, user User) error {
+	req, err := c.newRequest(ctx, http.MethodPut, c.usersResource(username), user)
+	if err != nil {
+		return err
+	}
+	if err := c.do(req, nil, http.StatusOK); err != nil {
+		return fmt.Errorf("Failed to update user %s: %w", username, err)
+	}
+	return nil
+}
+
+func (c *Client) DeleteUser(ctx context.Context, username string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, c.usersResource(username), nil)
+	if err != nil {
+		return err
+	}
+	if err := c.do(req, nil, http.StatusNoContent); err != nil {
+		return fmt.Errorf("Failed to delete user %s: %w", username, err)
+	}
+	return nil
+}
+
+func (c *Client) SecurityEnabled(ctx context.Context) (bool, error) {
+	var enabled bool
+
+	req, err := c.newRequest(ctx, http.MethodGet, "security", nil)
+	if err != nil {
+		return enabled, err
+	}
+	if err := c.do(req, &enabled, http.StatusOK); err != nil {
+		return enabled, fmt.Errorf("Failed to get security status: %w", err)
+	}
+	return enabled, nil
+}
+
+func (c *Client) GetAuthenticatedUser(ctx context.Context) (User, error) {
+	var user User
+
+	req, err := c.newRequest(ctx, http.MethodGet, "security/authenticatedUser", nil)
+	if err != nil {
+		return user, err
+	}
+	if err := c.do(req, &user, http.StatusOK); err != nil {
+		return user, fmt.Errorf("Failed to get authenticated user: %w", err)
+	}
+	return user, nil
+}
+
+// usersResource returns the REST resource of the given user, or of all users if username is empty.
+// GraphDB 9.x names the resource security/user rather than security/users.
+func (c *Client) usersResource(username string) string {
+	if c.legacyAPI() {
+		return "security/user/" + username
+	}
+	return "security/users/" + username
+}
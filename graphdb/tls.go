@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+)
+
+// TLSConfig holds the PEM encoded material used to secure the connection to GraphDB.
+type TLSConfig struct {
+	// CACertificate is a bundle of CA certificates trusted in addition to the system roots
+	CACertificate string
+	// ClientCertificate and ClientKey are used for mutual TLS authentication
+	ClientCertificate string
+	ClientKey         string
+	// Insecure disables verification of the server certificate
+	Insecure bool
+}
+
+func (t TLSConfig) build() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// #nosec G402 -- Explicitly requested by the caller
+		InsecureSkipVerify: t.Insecure,
+	}
+
+	if t.CACertificate != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(t.CACertificate)) {
+			return nil, errors.New("Unable to parse CA certificate bundle, no PEM encoded certificates found")
+		}
+		config.RootCAs = pool
+	}
+
+	if (t.ClientCertificate == "") != (t.ClientKey == "") {
+		return nil, errors.New("Client certificate and client key must be provided together")
+	}
+
+	if t.ClientCertificate != "" {
+		cert, err := tls.X509KeyPair([]byte(t.ClientCertificate), []byte(t.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("Unable to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
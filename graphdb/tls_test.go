@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTLSTestServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	return server, string(ca)
+}
+
+func tlsTestClient(t *testing.T, server *httptest.Server, config TLSConfig) *Client {
+	t.Helper()
+	return newTestClient(t, server.URL, WithTLS(config))
+}
+
+func TestTLSWithCACertificate(t *testing.T) {
+	server, ca := newTLSTestServer(t)
+	client := tlsTestClient(t, server, TLSConfig{CACertificate: ca})
+
+	if _, err := client.GetRepositories(context.Background()); err != nil {
+		t.Fatalf("Expected request to succeed with custom CA. Got: %s", err)
+	}
+}
+
+func TestTLSUntrustedCertificate(t *testing.T) {
+	server, _ := newTLSTestServer(t)
+	client := tlsTestClient(t, server, TLSConfig{})
+
+	if _, err := client.GetRepositories(context.Background()); err == nil {
+		t.Fatal("Expected request to fail with an untrusted server certificate")
+	}
+}
+
+func TestTLSInsecureSkipVerify(t *testing.T) {
+	server, _ := newTLSTestServer(t)
+	client := tlsTestClient(t, server, TLSConfig{Insecure: true})
+
+	if _, err := client.GetRepositories(context.Background()); err != nil {
+		t.Fatalf("Expected request to succeed when skipping verification. Got: %s", err)
+	}
+}
+
+func TestTLSInvalidCACertificate(t *testing.T) {
+	_, err := TLSConfig{CACertificate: "not a certificate"}.build()
+	if err == nil {
+		t.Fatal("Expected invalid CA bundle to fail")
+	}
+}
+
+func TestTLSClientCertificateWithoutKey(t *testing.T) {
+	_, ca := newTLSTestServer(t)
+	_, err := TLSConfig{ClientCertificate: ca}.build()
+	if err == nil {
+		t.Fatal("Expected client certificate without key to fail")
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// uploadProgressInterval is how many bytes are uploaded between progress log messages.
+const uploadProgressInterval = 8 * 1024 * 1024
+
+var errUploadRestarted = errors.New("Upload restarted")
+
+// upload streams a file to GraphDB as a multipart form, without holding it in memory.
+// The form is written to a pipe by a separate goroutine as the HTTP client reads the request body,
+// optionally compressing it with gzip.
+//
+// If the content can be seeked, the upload can be restarted, so that the request can be retried
+// or replayed after refreshing expired credentials. Otherwise, it can only be sent once.
+type upload struct {
+	ctx      context.Context
+	resource string
+	field    string
+	filename string
+	content  io.Reader
+	compress bool
+	boundary string
+
+	// reader and done belong to the most recent attempt
+	reader *io.PipeReader
+	done   chan struct{}
+}
+
+// newUploadRequest creates a request which uploads content as the given field of a multipart form.
+func (c *Client) newUploadRequest(ctx context.Context, method string, resource string, field string, filename string, content io.Reader) (*http.Request, error) {
+	u := &upload{
+		ctx:      ctx,
+		resource: resource,
+		field:    field,
+		filename: filename,
+		content:  content,
+		compress: c.compressUploads,
+		boundary: multipart.NewWriter(io.Discard).Boundary(),
+	}
+
+	body, err := u.open()
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.createUrl(resource), body)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+u.boundary)
+	if u.compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if _, ok := content.(io.Seeker); ok {
+		req.GetBody = u.open
+	}
+	return req, nil
+}
+
+// open starts streaming the form, abandoning any previous attempt and rewinding the content.
+func (u *upload) open() (io.ReadCloser, error) {
+	if u.done != nil {
+		// Wait for the previous attempt to stop reading the content before rewinding it
+		u.reader.CloseWithError(errUploadRestarted)
+		<-u.done
+		seeker, ok := u.content.(io.Seeker)
+		if !ok {
+			return nil, errors.New("Upload cannot be restarted, as its content cannot be rewound")
+		}
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("Failed to rewind upload: %w", err)
+		}
+	}
+
+	reader, writer := io.Pipe()
+	done := make(chan struct{})
+	u.reader, u.done = reader, done
+	go func() {
+		defer close(done)
+		writer.CloseWithError(u.write(writer))
+	}()
+	return reader, nil
+}
+
+func (u *upload) write(w io.Writer) error {
+	var gz *gzip.Writer
+	if u.compress {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+
+	form := multipart.NewWriter(w)
+	if err := form.SetBoundary(u.boundary); err != nil {
+		return err
+	}
+	part, err := form.CreateFormFile(u.field, u.filename)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(part, &progressReader{ctx: u.ctx, reader: u.content, resource: u.resource})
+	if err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return err
+		}
+	}
+
+	tflog.Debug(u.ctx, "Finished uploading to GraphDB", map[string]any{"resource": u.resource, "bytes": n})
+	return nil
+}
+
+// progressReader logs how much of an upload has been read, every uploadProgressInterval bytes.
+type progressReader struct {
+	ctx      context.Context
+	reader   io.Reader
+	resource string
+
+	read   int64
+	logged int64
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	p.read += int64(n)
+	if p.read-p.logged >= uploadProgressInterval {
+		p.logged = p.read
+		tflog.Debug(p.ctx, "Uploading to GraphDB", map[string]any{"resource": p.resource, "bytes": p.read})
+	}
+	return n, err
+}
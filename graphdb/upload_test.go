@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package graphdb
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newUploadTestServer records the size of each uploaded config, answering 429 Too Many Requests to the first
+// failures attempts.
+func newUploadTestServer(t *testing.T, failures int) (*httptest.Server, *[]int64) {
+	t.Helper()
+	var sizes []int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			body, err := gzip.NewReader(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			r.Body = body
+		}
+		file, _, err := r.FormFile("config")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		n, _ := io.Copy(io.Discard, file)
+		sizes = append(sizes, n)
+
+		if len(sizes) <= failures {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+	return server, &sizes
+}
+
+// onceReader hides the Seek method of the underlying reader, so that uploads cannot be restarted.
+type onceReader struct {
+	io.Reader
+}
+
+func TestUploadStreamsLargeContent(t *testing.T) {
+	server, sizes := newUploadTestServer(t, 0)
+	const size = 3*uploadProgressInterval + 123
+
+	for _, compress := range []bool{false, true} {
+		opts := []Option{}
+		if compress {
+			opts = append(opts, WithUploadCompression())
+		}
+		content := onceReader{strings.NewReader(strings.Repeat("a", size))}
+		if err := newTestClient(t, server.URL, opts...).CreateRepository(context.Background(), content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(*sizes) != 2 || (*sizes)[0] != size || (*sizes)[1] != size {
+		t.Fatalf("Unexpected uploaded sizes: %v", *sizes)
+	}
+}
+
+func TestUploadRetriesSeekableContent(t *testing.T) {
+	server, sizes := newUploadTestServer(t, 1)
+	client := newTestClient(t, server.URL, WithRetry(1, time.Millisecond))
+
+	if err := client.CreateRepository(context.Background(), strings.NewReader("config")); err != nil {
+		t.Fatal(err)
+	}
+	if len(*sizes) != 2 || (*sizes)[1] != int64(len("config")) {
+		t.Fatalf("Expected the complete config to be uploaded again. Got: %v", *sizes)
+	}
+}
+
+func TestUploadDoesNotRetryStreamedContent(t *testing.T) {
+	server, sizes := newUploadTestServer(t, 1)
+	client := newTestClient(t, server.URL, WithRetry(1, time.Millisecond))
+
+	if err := client.CreateRepository(context.Background(), onceReader{strings.NewReader("config")}); err == nil {
+		t.Fatal("Expected upload of content which cannot be rewound not to be retried")
+	}
+	if len(*sizes) != 1 {
+		t.Fatalf("Expected a single attempt. Got: %d", len(*sizes))
+	}
+}
@@ -4,8 +4,38 @@
 package provider
 
 const (
+	EnvEndpoint = "GRAPHDB_ENDPOINT"
 	EnvHost     = "GRAPHDB_HOST"
 	EnvPort     = "GRAPHDB_PORT"
 	EnvUsername = "GRAPHDB_USERNAME"
 	EnvPassword = "GRAPHDB_PASSWORD"
+	EnvAuthMode = "GRAPHDB_AUTH_MODE"
+
+	EnvEndpoints = "GRAPHDB_ENDPOINTS"
+
+	EnvBearerToken = "GRAPHDB_BEARER_TOKEN"
+
+	EnvMaxRetries   = "GRAPHDB_MAX_RETRIES"
+	EnvRetryMaxWait = "GRAPHDB_RETRY_MAX_WAIT"
+
+	EnvMaxConcurrentRequests = "GRAPHDB_MAX_CONCURRENT_REQUESTS"
+
+	EnvCompressUploads = "GRAPHDB_COMPRESS_UPLOADS"
+	EnvHTTPCaptureFile = "GRAPHDB_HTTP_CAPTURE_FILE"
+
+	EnvSkipConnectivityCheck = "GRAPHDB_SKIP_CONNECTIVITY_CHECK"
+
+	EnvScheme             = "GRAPHDB_SCHEME"
+	EnvCACertificate      = "GRAPHDB_CA_CERTIFICATE"
+	EnvClientCertificate  = "GRAPHDB_CLIENT_CERTIFICATE"
+	EnvClientKey          = "GRAPHDB_CLIENT_KEY"
+	EnvInsecureSkipVerify = "GRAPHDB_INSECURE_SKIP_VERIFY"
+)
+
+// Supported values of the provider auth_mode attribute.
+const (
+	authModeBasic  = "basic"
+	authModeToken  = "token"
+	authModeBearer = "bearer"
+	authModeOAuth2 = "oauth2"
 )
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
 )
 
 var (
@@ -17,7 +18,7 @@ var (
 )
 
 type repositoryDataSource struct {
-	client *Client
+	client graphdb.RepositoriesAPI
 }
 
 type repositoriesDataSourceModel struct {
@@ -51,7 +52,7 @@ func (d *repositoryDataSource) Configure(
 		return
 	}
 
-	client, ok := req.ProviderData.(*Client)
+	client, ok := req.ProviderData.(graphdb.RepositoriesAPI)
 	if !ok {
 		unexpectedDataSourceConfigureType(ctx, req, resp)
 	}
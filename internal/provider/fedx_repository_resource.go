@@ -0,0 +1,604 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
+)
+
+var (
+	_ resource.Resource                   = &FedXRepositoryResource{}
+	_ resource.ResourceWithImportState    = &FedXRepositoryResource{}
+	_ resource.ResourceWithModifyPlan     = &FedXRepositoryResource{}
+	_ resource.ResourceWithValidateConfig = &FedXRepositoryResource{}
+)
+
+// fedxTuningParams maps the attributes of the tuning block to the FedX parameters they set.
+var fedxTuningParams = map[string]string{
+	"join_worker_threads":          "joinWorkerThreads",
+	"left_join_worker_threads":     "leftJoinWorkerThreads",
+	"union_worker_threads":         "unionWorkerThreads",
+	"bound_join_block_size":        "boundJoinBlockSize",
+	"enforce_max_query_time":       "enforceMaxQueryTime",
+	"include_inferred_default":     "includeInferredDefault",
+	"enable_service_as_bound_join": "enableServiceAsBoundJoin",
+	"source_selection_cache_spec":  "sourceSelectionCacheSpec",
+}
+
+// fedxMemberStores maps the member types to the FedX stores implementing them.
+var fedxMemberStores = map[string]string{
+	"local":  graphdb.FedXStoreLocal,
+	"remote": graphdb.FedXStoreRemote,
+	"sparql": graphdb.FedXStoreSPARQL,
+}
+
+type FedXRepositoryResource struct {
+	client graphdb.RepositoriesAPI
+	server graphdb.InfoAPI
+}
+
+type FedXRepositoryResourceModel struct {
+	ID          types.String      `tfsdk:"id"`
+	Name        types.String      `tfsdk:"name"`
+	Description types.String      `tfsdk:"description"`
+	Location    types.String      `tfsdk:"location"`
+	Members     []FedXMemberModel `tfsdk:"member"`
+	Tuning      types.Object      `tfsdk:"tuning"`
+}
+
+type FedXMemberModel struct {
+	Type               types.String `tfsdk:"type"`
+	Repository         types.String `tfsdk:"repository"`
+	ServerURL          types.String `tfsdk:"server_url"`
+	Endpoint           types.String `tfsdk:"endpoint"`
+	Username           types.String `tfsdk:"username"`
+	Password           types.String `tfsdk:"password"`
+	Writable           types.Bool   `tfsdk:"writable"`
+	RespectRights      types.Bool   `tfsdk:"respect_rights"`
+	SupportsASKQueries types.Bool   `tfsdk:"supports_ask_queries"`
+}
+
+func NewFedXRepositoryResource() resource.Resource {
+	return &FedXRepositoryResource{}
+}
+
+func (r *FedXRepositoryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(graphdb.RepositoriesAPI)
+	if !ok {
+		ProviderDataError(req.ProviderData, &resp.Diagnostics)
+		return
+	}
+
+	r.client = client
+	r.server, _ = req.ProviderData.(graphdb.InfoAPI)
+}
+
+func (r *FedXRepositoryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_fedx_repository"
+}
+
+func (r *FedXRepositoryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "GraphDB FedX repository, federating queries over other repositories and SPARQL endpoints." +
+			" Members and tuning options are changed in place.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "An identifier for the resource, the name prefixed with the location for repositories in a remote location",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Repository name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Repository Description",
+			},
+			"location": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "URL of a remote location attached to GraphDB to manage the repository in, such as another GraphDB instance." +
+					" Defaults to the local GraphDB instance. Changing it recreates the repository, except for adding or removing a trailing slash.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "must be an http or https URL"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIf(locationRequiresReplace,
+						"Changing the location recreates the repository.",
+						"Changing the location recreates the repository."),
+				}},
+			"tuning": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "FedX query processing options. Options which are not set use the GraphDB defaults.",
+				Attributes: map[string]schema.Attribute{
+					"join_worker_threads": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Number of threads evaluating joins.",
+						Validators:  []validator.Int64{int64validator.AtLeast(1)},
+					},
+					"left_join_worker_threads": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Number of threads evaluating left joins, i.e. OPTIONAL.",
+						Validators:  []validator.Int64{int64validator.AtLeast(1)},
+					},
+					"union_worker_threads": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Number of threads evaluating unions.",
+						Validators:  []validator.Int64{int64validator.AtLeast(1)},
+					},
+					"bound_join_block_size": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Number of bindings sent to a member in a single bound join request.",
+						Validators:  []validator.Int64{int64validator.AtLeast(1)},
+					},
+					"enforce_max_query_time": schema.Int64Attribute{
+						Optional:    true,
+						Description: "Number of seconds after which queries are stopped, 0 for no limit.",
+						Validators:  []validator.Int64{int64validator.AtLeast(0)},
+					},
+					"include_inferred_default": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Include inferred statements in query results unless the query says otherwise.",
+					},
+					"enable_service_as_bound_join": schema.BoolAttribute{
+						Optional:    true,
+						Description: "Evaluate SERVICE clauses as bound joins.",
+					},
+					"source_selection_cache_spec": schema.StringAttribute{
+						Optional:    true,
+						Description: "Specification of the source selection cache, e.g. maximumSize=1000,expireAfterWrite=6h.",
+					},
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"member": schema.ListNestedBlock{
+				Description: "Members of the federation. Changing them updates the repository in place.",
+				Validators: []validator.List{
+					listvalidator.IsRequired(),
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Required: true,
+							Description: "Type of the member: local for a repository of the same GraphDB instance, remote for a repository of another GraphDB instance," +
+								" or sparql for a SPARQL endpoint.",
+							Validators: []validator.String{
+								stringvalidator.OneOf("local", "remote", "sparql"),
+							},
+						},
+						"repository": schema.StringAttribute{
+							Optional:    true,
+							Description: "Name of the repository, for local and remote members.",
+						},
+						"server_url": schema.StringAttribute{
+							Optional:    true,
+							Description: "URL of the GraphDB instance hosting the repository, for remote members.",
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "must be an http or https URL"),
+							},
+						},
+						"endpoint": schema.StringAttribute{
+							Optional:    true,
+							Description: "URL of the SPARQL endpoint, for sparql members.",
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "must be an http or https URL"),
+							},
+						},
+						"username": schema.StringAttribute{
+							Optional:    true,
+							Description: "User to connect to the remote GraphDB instance as.",
+						},
+						"password": schema.StringAttribute{
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Password of the user connecting to the remote GraphDB instance.",
+						},
+						"writable": schema.BoolAttribute{
+							Optional:    true,
+							Description: "Send updates to this member. At most one member can be writable, and SPARQL endpoints cannot be.",
+						},
+						"respect_rights": schema.BoolAttribute{
+							Optional:    true,
+							Description: "Check the access rights of the user running the query on the repository, for local members.",
+						},
+						"supports_ask_queries": schema.BoolAttribute{
+							Optional:    true,
+							Description: "Use ASK queries to select the sources of a query, for sparql members.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that each member sets the attributes its type requires, and only those. Members which are not
+// known yet, e.g. generated by a dynamic block, are checked once they are.
+func (r *FedXRepositoryResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var members types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("member"), &members)...)
+	if resp.Diagnostics.HasError() || members.IsNull() || members.IsUnknown() {
+		return
+	}
+
+	writable := 0
+	for i, element := range members.Elements() {
+		memberPath := path.Root("member").AtListIndex(i)
+		member, ok := element.(types.Object)
+		if !ok || member.IsNull() || member.IsUnknown() {
+			continue
+		}
+		var m FedXMemberModel
+		resp.Diagnostics.Append(member.As(ctx, &m, basetypes.ObjectAsOptions{})...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if m.Type.IsUnknown() || m.Type.IsNull() {
+			continue
+		}
+		attributes := map[string]types.String{
+			"repository": m.Repository,
+			"server_url": m.ServerURL,
+			"endpoint":   m.Endpoint,
+			"username":   m.Username,
+			"password":   m.Password,
+		}
+		var required []string
+		allowed := map[string]bool{}
+		switch m.Type.ValueString() {
+		case "local":
+			required = []string{"repository"}
+		case "remote":
+			required = []string{"repository", "server_url"}
+			allowed["username"], allowed["password"] = true, true
+		case "sparql":
+			required = []string{"endpoint"}
+		}
+		for _, name := range required {
+			allowed[name] = true
+		}
+		for _, name := range required {
+			if attributes[name].IsNull() {
+				resp.Diagnostics.AddAttributeError(memberPath.AtName(name), "Missing FedX member attribute",
+					fmt.Sprintf("A %s member must set %s.", m.Type.ValueString(), name))
+			}
+		}
+		for name, value := range attributes {
+			if !value.IsNull() && !allowed[name] {
+				resp.Diagnostics.AddAttributeError(memberPath.AtName(name), "Invalid FedX member attribute",
+					fmt.Sprintf("A %s member cannot set %s.", m.Type.ValueString(), name))
+			}
+		}
+		if !m.RespectRights.IsNull() && m.Type.ValueString() != "local" {
+			resp.Diagnostics.AddAttributeError(memberPath.AtName("respect_rights"), "Invalid FedX member attribute",
+				fmt.Sprintf("A %s member cannot set respect_rights.", m.Type.ValueString()))
+		}
+		if !m.SupportsASKQueries.IsNull() && m.Type.ValueString() != "sparql" {
+			resp.Diagnostics.AddAttributeError(memberPath.AtName("supports_ask_queries"), "Invalid FedX member attribute",
+				fmt.Sprintf("A %s member cannot set supports_ask_queries.", m.Type.ValueString()))
+		}
+		if m.Writable.ValueBool() {
+			if m.Type.ValueString() == "sparql" {
+				resp.Diagnostics.AddAttributeError(memberPath.AtName("writable"), "Invalid FedX member attribute", "A SPARQL endpoint cannot be writable.")
+			}
+			writable++
+		}
+	}
+	if writable > 1 {
+		resp.Diagnostics.AddAttributeError(path.Root("member"), "Too many writable FedX members",
+			fmt.Sprintf("FedX sends updates to a single member, but %d members are writable.", writable))
+	}
+}
+
+func (r *FedXRepositoryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when the repository is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	requireFeature(r.server, graphdb.FeatureFedX, path.Root("member"), &resp.Diagnostics)
+}
+
+func (r *FedXRepositoryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan FedXRepositoryResourceModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	location := canonicalLocation(plan.Location.ValueString())
+	repos := r.client.InLocation(location)
+	unlock, err := repos.LockRepository(ctx, plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create Repository", fmt.Sprintf("Interrupted while waiting for other operations on the repository. Error: %s", err))
+		return
+	}
+	defer unlock()
+
+	repo := graphdb.Repository{
+		ID:     plan.Name.ValueString(),
+		Params: settingsParams(plan.Tuning, fedxTuningParams),
+	}
+	if !plan.Description.IsUnknown() {
+		repo.Title = plan.Description.ValueString()
+	}
+	config := graphdb.FormatFedXConfig(repo, fedxMembers(plan.Members))
+	if err := repos.CreateRepository(ctx, strings.NewReader(config)); err != nil {
+		resp.Diagnostics.AddError("Failed to create Repository", fmt.Sprintf("Failed to create repository. Unexpected error %s", err.Error()))
+		return
+	}
+	if err := r.doRead(ctx, location, repo.ID, &plan, &resp.Diagnostics); err != nil {
+		resp.Diagnostics.AddError("Failed to create Repository", fmt.Sprintf("Failed to retrieve repository after creation. Unexpected error %s", err.Error()))
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *FedXRepositoryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state FedXRepositoryResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	repoName := state.ID.ValueString()
+	tflog.Debug(ctx, "Fetching repository", map[string]any{"id": repoName})
+	location, name := parseRepositoryID(repoName)
+	err := r.doRead(ctx, location, name, &state, &resp.Diagnostics)
+	if graphdb.IsNotFound(err) {
+		tflog.Warn(ctx, "Repository no longer exists, removing from state", map[string]any{"id": repoName})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read repository", fmt.Sprintf("Unable to read repository. Unexpected error: %s", err))
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *FedXRepositoryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state FedXRepositoryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	repoId := state.ID.ValueString()
+	tflog.Debug(ctx, "Updating repository", map[string]any{"id": repoId})
+	location, name := parseRepositoryID(repoId)
+	repos := r.client.InLocation(location)
+
+	unlock, err := repos.LockRepository(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Interrupted while waiting for other operations on the repository. Error: %s", err))
+		return
+	}
+	defer unlock()
+
+	// GraphDB replaces the whole configuration, so start from the current one and apply the planned changes to it
+	repo, err := repos.GetRepository(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Failed to retrieve repository before update. Unexpected error %s", err.Error()))
+		return
+	}
+	repo.Params = updatedFedXParams(repo.Params, settingsParams(plan.Tuning, fedxTuningParams))
+	members, err := graphdb.NewFedXMembersParam(fedxMembers(plan.Members))
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Unexpected error %s", err.Error()))
+		return
+	}
+	if current, ok := repo.Params[graphdb.FedXMembersParam]; ok {
+		members.Label = current.Label
+	}
+	repo.Params[graphdb.FedXMembersParam] = members
+	if !plan.Description.IsUnknown() && !plan.Description.IsNull() {
+		repo.Title = plan.Description.ValueString()
+	}
+
+	if err := repos.UpdateRepository(ctx, name, repo); err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Failed to update repository. Unexpected error %s", err.Error()))
+		return
+	}
+	if err := r.doRead(ctx, location, name, &plan, &resp.Diagnostics); err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Failed to retrieve repository after update. Unexpected error %s", err.Error()))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *FedXRepositoryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state FedXRepositoryResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	repoId := state.ID.ValueString()
+	tflog.Debug(ctx, "Deleting repository", map[string]any{"id": repoId})
+	location, name := parseRepositoryID(repoId)
+	repos := r.client.InLocation(location)
+
+	unlock, err := repos.LockRepository(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting repository", fmt.Sprintf("Interrupted while waiting for other operations on the repository. Error: %s", err))
+		return
+	}
+	defer unlock()
+
+	err = repos.DeleteRepository(ctx, name)
+	if graphdb.IsNotFound(err) {
+		tflog.Debug(ctx, "Repository already deleted", map[string]any{"id": repoId})
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting repository", fmt.Sprintf("Could not delete repository. Unexpected error: %s", err.Error()))
+		return
+	}
+}
+
+// ImportState imports a repository by its ID, like graphdb_repository. Member passwords are not imported.
+func (r *FedXRepositoryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// doRead refreshes the repository and its members, which are read from the live configuration.
+func (r *FedXRepositoryResource) doRead(ctx context.Context, location string, name string, data *FedXRepositoryResourceModel, diags *diag.Diagnostics) error {
+	repos := r.client.InLocation(location)
+	repo, err := repos.GetRepository(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	data.ID = types.StringValue(repositoryID(location, repo.ID))
+	data.Name = types.StringValue(repo.ID)
+	data.Description = types.StringValue(repo.Title)
+	data.Location = refreshLocation(data.Location, location)
+	data.Tuning = refreshSettings(ctx, data.Tuning, fedxTuningParams, repo.Params, diags)
+
+	config, err := repos.GetRepositoryConfig(ctx, name)
+	if graphdb.IsNotFound(err) {
+		tflog.Debug(ctx, "GraphDB cannot download repository configurations, keeping the members in state", map[string]any{"id": name})
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	members, err := graphdb.ParseFedXMembers(config)
+	if err != nil {
+		diags.AddWarning("Unable to read FedX members", fmt.Sprintf("Failed to parse the live repository configuration. Error: %s", err))
+		return nil
+	}
+	data.Members = refreshFedXMembers(data.Members, members)
+	return nil
+}
+
+// updatedFedXParams returns the parameters to update a FedX repository with, given its live parameters and the planned
+// tuning options. Tuning options which are no longer planned are left out, so that GraphDB resets them to their
+// defaults, while the other parameters, such as the members, are kept. The labels of the live parameters are kept.
+func updatedFedXParams(live map[string]graphdb.RepositoryParam, planned map[string]graphdb.RepositoryParam) map[string]graphdb.RepositoryParam {
+	tuning := map[string]bool{}
+	for _, name := range fedxTuningParams {
+		tuning[name] = true
+	}
+	params := map[string]graphdb.RepositoryParam{}
+	for name, param := range live {
+		if !tuning[name] {
+			params[name] = param
+		}
+	}
+	for name, param := range planned {
+		if current, ok := live[name]; ok {
+			param.Label = current.Label
+		}
+		params[name] = param
+	}
+	return params
+}
+
+// fedxMembers converts the member blocks to FedX members.
+func fedxMembers(models []FedXMemberModel) []graphdb.FedXMember {
+	members := make([]graphdb.FedXMember, 0, len(models))
+	for _, m := range models {
+		members = append(members, graphdb.FedXMember{
+			Store:              fedxMemberStores[m.Type.ValueString()],
+			RepositoryName:     m.Repository.ValueString(),
+			RepositoryServer:   m.ServerURL.ValueString(),
+			Endpoint:           m.Endpoint.ValueString(),
+			Username:           m.Username.ValueString(),
+			Password:           m.Password.ValueString(),
+			RespectRights:      m.RespectRights.ValueBoolPointer(),
+			Writable:           m.Writable.ValueBoolPointer(),
+			SupportsASKQueries: m.SupportsASKQueries.ValueBoolPointer(),
+		})
+	}
+	return members
+}
+
+// refreshFedXMembers returns the member blocks for the live members. Flags which are not set in current are left unset
+// if the live configuration does not change them from the default, and passwords are kept from current, as GraphDB
+// may not return them.
+func refreshFedXMembers(current []FedXMemberModel, live []graphdb.FedXMember) []FedXMemberModel {
+	models := make([]FedXMemberModel, 0, len(live))
+	for i, m := range live {
+		var previous FedXMemberModel
+		if i < len(current) {
+			previous = current[i]
+		}
+		model := FedXMemberModel{
+			Type:               types.StringNull(),
+			Repository:         optionalString(m.RepositoryName),
+			ServerURL:          optionalString(m.RepositoryServer),
+			Endpoint:           optionalString(m.Endpoint),
+			Username:           optionalString(m.Username),
+			Password:           optionalString(m.Password),
+			Writable:           refreshFlag(previous.Writable, m.Writable, false),
+			RespectRights:      refreshFlag(previous.RespectRights, m.RespectRights, false),
+			SupportsASKQueries: refreshFlag(previous.SupportsASKQueries, m.SupportsASKQueries, true),
+		}
+		for memberType, store := range fedxMemberStores {
+			if store == m.Store {
+				model.Type = types.StringValue(memberType)
+			}
+		}
+		if model.Type.Equal(previous.Type) && model.ServerURL.Equal(previous.ServerURL) && model.Repository.Equal(previous.Repository) {
+			model.Password = previous.Password
+		}
+		models = append(models, model)
+	}
+	return models
+}
+
+func refreshFlag(previous types.Bool, live *bool, defaultValue bool) types.Bool {
+	if live == nil || previous.IsNull() && *live == defaultValue {
+		return types.BoolNull()
+	}
+	return types.BoolValue(*live)
+}
+
+func optionalString(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
@@ -0,0 +1,230 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb/graphdbtest"
+)
+
+func TestFedXRepositoryResource(t *testing.T) {
+	server := newTestServer(t)
+	config := func(members string, tuning string) string {
+		return testProviderConfig(server) + fmt.Sprintf(`
+resource "graphdb_fedx_repository" "test" {
+  name        = "Federation"
+  description = "Federation"
+%s
+  tuning = {
+    join_worker_threads = 10
+    %s
+  }
+}
+`, members, tuning)
+	}
+	local := `
+  member {
+    type       = "local"
+    repository = "people"
+    writable   = true
+  }
+`
+	remote := `
+  member {
+    type       = "remote"
+    server_url = "http://remote:7200"
+    repository = "cities"
+    username   = "reader"
+    password   = "secret"
+  }
+`
+	sparql := `
+  member {
+    type                 = "sparql"
+    endpoint             = "https://dbpedia.org/sparql"
+    supports_ask_queries = false
+  }
+`
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Test Create and Read
+			{
+				Config: config(local+remote, "include_inferred_default = false"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_fedx_repository.test", "id", "Federation"),
+					resource.TestCheckResourceAttr("graphdb_fedx_repository.test", "member.#", "2"),
+					resource.TestCheckResourceAttr("graphdb_fedx_repository.test", "member.1.password", "secret"),
+					checkFedXMembers(server,
+						graphdb.FedXMember{Store: graphdb.FedXStoreLocal, RepositoryName: "people"},
+						graphdb.FedXMember{Store: graphdb.FedXStoreRemote, RepositoryName: "cities"},
+					),
+					func(_ *terraform.State) error {
+						repo, _ := server.Repository("Federation")
+						if repo.Type != "fedx" || repo.Params["joinWorkerThreads"].Value != "10" {
+							return fmt.Errorf("Unexpected repository: %+v", repo)
+						}
+						return nil
+					},
+				),
+			},
+			// Test that membership changes update the repository in place
+			{
+				Config: config(remote+sparql, "include_inferred_default = false"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_fedx_repository.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_fedx_repository.test", "member.#", "2"),
+					resource.TestCheckResourceAttr("graphdb_fedx_repository.test", "member.1.supports_ask_queries", "false"),
+					checkFedXMembers(server,
+						graphdb.FedXMember{Store: graphdb.FedXStoreRemote, RepositoryName: "cities"},
+						graphdb.FedXMember{Store: graphdb.FedXStoreSPARQL, Endpoint: "https://dbpedia.org/sparql"},
+					),
+					func(_ *terraform.State) error {
+						if updates := server.RepositoryUpdates("Federation"); updates != 1 {
+							return fmt.Errorf("Expected a single update. Got: %d", updates)
+						}
+						return nil
+					},
+				),
+			},
+			// Test that a tuning option removed from the configuration is removed from the repository, so that GraphDB
+			// resets it to its default
+			{
+				Config: config(remote+sparql, ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_fedx_repository.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkFedXMembers(server,
+						graphdb.FedXMember{Store: graphdb.FedXStoreRemote, RepositoryName: "cities"},
+						graphdb.FedXMember{Store: graphdb.FedXStoreSPARQL, Endpoint: "https://dbpedia.org/sparql"},
+					),
+					func(_ *terraform.State) error {
+						repo, _ := server.Repository("Federation")
+						if _, ok := repo.Params["includeInferredDefault"]; ok || repo.Params["joinWorkerThreads"].Value != "10" {
+							return fmt.Errorf("Expected only the join worker threads to be left. Got: %+v", repo.Params)
+						}
+						if updates := server.RepositoryUpdates("Federation"); updates != 2 {
+							return fmt.Errorf("Expected a second update. Got: %d", updates)
+						}
+						return nil
+					},
+				),
+			},
+			// Test import, GraphDB may not return passwords, and only the tuning options which are set are refreshed
+			{
+				ResourceName:            "graphdb_fedx_repository.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"member.0.password", "tuning"},
+			},
+		},
+	})
+}
+
+// TestFedXRepositoryResourceUnknownMembers checks that members generated from values only known after apply are valid.
+func TestFedXRepositoryResourceUnknownMembers(t *testing.T) {
+	server := newTestServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig(server) + `
+resource "terraform_data" "repositories" {
+  input = ["people", "cities"]
+}
+
+resource "graphdb_fedx_repository" "test" {
+  name = "Federation"
+  dynamic "member" {
+    for_each = terraform_data.repositories.output
+    content {
+      type       = "local"
+      repository = member.value
+    }
+  }
+}
+`,
+				Check: checkFedXMembers(server,
+					graphdb.FedXMember{Store: graphdb.FedXStoreLocal, RepositoryName: "people"},
+					graphdb.FedXMember{Store: graphdb.FedXStoreLocal, RepositoryName: "cities"},
+				),
+			},
+		},
+	})
+}
+
+func TestFedXRepositoryResourceInvalidMembers(t *testing.T) {
+	server := newTestServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig(server) + `
+resource "graphdb_fedx_repository" "test" {
+  name = "Federation"
+  member {
+    type       = "remote"
+    repository = "cities"
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`A remote member must set server_url`),
+			},
+			{
+				Config: testProviderConfig(server) + `
+resource "graphdb_fedx_repository" "test" {
+  name = "Federation"
+  member {
+    type       = "local"
+    repository = "people"
+    writable   = true
+  }
+  member {
+    type       = "local"
+    repository = "cities"
+    writable   = true
+  }
+}
+`,
+				ExpectError: regexp.MustCompile(`but 2 members are writable`),
+			},
+		},
+	})
+}
+
+// checkFedXMembers checks the members of the Federation repository on the fake server.
+func checkFedXMembers(server *graphdbtest.Server, want ...graphdb.FedXMember) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		repo, _ := server.Repository("Federation")
+		members, err := graphdbtest.FedXMembers(repo)
+		if err != nil {
+			return err
+		}
+		if len(members) != len(want) {
+			return fmt.Errorf("Unexpected members. Wanted: %+v. Got: %+v", want, members)
+		}
+		for i, m := range members {
+			if m.Store != want[i].Store || m.RepositoryName != want[i].RepositoryName || m.Endpoint != want[i].Endpoint {
+				return fmt.Errorf("Unexpected member %d. Wanted: %+v. Got: %+v", i, want[i], m)
+			}
+		}
+		return nil
+	}
+}
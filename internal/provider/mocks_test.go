@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
+)
+
+// mockSecurityAPI is an in-memory graphdb.SecurityAPI for unit testing resources.
+type mockSecurityAPI struct {
+	users map[string]graphdb.User
+}
+
+var _ graphdb.SecurityAPI = &mockSecurityAPI{}
+
+func newMockSecurityAPI(users ...graphdb.User) *mockSecurityAPI {
+	m := &mockSecurityAPI{users: map[string]graphdb.User{}}
+	for _, u := range users {
+		m.users[u.Username] = u
+	}
+	return m
+}
+
+func (m *mockSecurityAPI) notFound(username string) error {
+	return &graphdb.APIError{StatusCode: http.StatusNotFound, Method: http.MethodGet, URL: "security/users/" + username}
+}
+
+func (m *mockSecurityAPI) GetUsers(_ context.Context) ([]graphdb.User, error) {
+	var users []graphdb.User
+	for _, u := range m.users {
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+func (m *mockSecurityAPI) GetUser(_ context.Context, username string) (graphdb.User, error) {
+	u, ok := m.users[username]
+	if !ok {
+		return u, m.notFound(username)
+	}
+	return u, nil
+}
+
+func (m *mockSecurityAPI) CreateUser(_ context.Context, user graphdb.User) error {
+	m.users[user.Username] = user
+	return nil
+}
+
+func (m *mockSecurityAPI) UpdateUser(_ context.Context, username string, user graphdb.User) error {
+	if _, ok := m.users[username]; !ok {
+		return m.notFound(username)
+	}
+	m.users[username] = user
+	return nil
+}
+
+func (m *mockSecurityAPI) DeleteUser(_ context.Context, username string) error {
+	if _, ok := m.users[username]; !ok {
+		return m.notFound(username)
+	}
+	delete(m.users, username)
+	return nil
+}
+
+func (m *mockSecurityAPI) SecurityEnabled(_ context.Context) (bool, error) {
+	return true, nil
+}
+
+func (m *mockSecurityAPI) GetAuthenticatedUser(_ context.Context) (graphdb.User, error) {
+	return graphdb.User{Username: "admin", Authorities: []string{"ROLE_ADMIN"}}, nil
+}
@@ -0,0 +1,477 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
+)
+
+var (
+	_ resource.Resource                = &OntopRepositoryResource{}
+	_ resource.ResourceWithImportState = &OntopRepositoryResource{}
+	_ resource.ResourceWithModifyPlan  = &OntopRepositoryResource{}
+)
+
+// Parameters of an Ontop repository, which hold the paths of the uploaded files.
+const (
+	ontopMappingParam    = "obdaFile"
+	ontopOntologyParam   = "owlFile"
+	ontopPropertiesParam = "propertiesFile"
+	ontopConstraintParam = "constraintFile"
+)
+
+// ontopMappingExtensions are the extensions GraphDB uses to recognise the format of an uploaded mapping.
+var ontopMappingExtensions = map[string]string{
+	"obda":  ".obda",
+	"r2rml": ".ttl",
+}
+
+type OntopRepositoryResource struct {
+	client graphdb.RepositoriesAPI
+	server graphdb.InfoAPI
+}
+
+type OntopRepositoryResourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Description   types.String `tfsdk:"description"`
+	Location      types.String `tfsdk:"location"`
+	Mapping       types.String `tfsdk:"mapping"`
+	MappingFormat types.String `tfsdk:"mapping_format"`
+	Ontology      types.String `tfsdk:"ontology"`
+	Constraint    types.String `tfsdk:"constraint"`
+	JDBCURL       types.String `tfsdk:"jdbc_url"`
+	JDBCDriver    types.String `tfsdk:"jdbc_driver"`
+	JDBCUser      types.String `tfsdk:"jdbc_user"`
+	JDBCPassword  types.String `tfsdk:"jdbc_password"`
+}
+
+func NewOntopRepositoryResource() resource.Resource {
+	return &OntopRepositoryResource{}
+}
+
+func (r *OntopRepositoryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(graphdb.RepositoriesAPI)
+	if !ok {
+		ProviderDataError(req.ProviderData, &resp.Diagnostics)
+		return
+	}
+
+	r.client = client
+	r.server, _ = req.ProviderData.(graphdb.InfoAPI)
+}
+
+func (r *OntopRepositoryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ontop_repository"
+}
+
+func (r *OntopRepositoryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "GraphDB Ontop virtual repository, exposing a relational database as a read-only RDF graph through a mapping." +
+			" The mapping, ontology, constraint and database connection are uploaded to GraphDB, and changing them updates the repository in place.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "An identifier for the resource, the name prefixed with the location for repositories in a remote location",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "Repository name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Repository Description",
+			},
+			"location": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "URL of a remote location attached to GraphDB to manage the repository in, such as another GraphDB instance." +
+					" Defaults to the local GraphDB instance. Changing it recreates the repository, except for adding or removing a trailing slash.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "must be an http or https URL"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIf(locationRequiresReplace,
+						"Changing the location recreates the repository.",
+						"Changing the location recreates the repository."),
+				}},
+			"mapping": schema.StringAttribute{
+				Required:    true,
+				Description: "Mapping from the database to RDF, in the format given by mapping_format.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"mapping_format": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("obda"),
+				Description: "Format of the mapping, either obda for the native Ontop format or r2rml for an R2RML mapping in Turtle syntax. Defaults to obda.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("obda", "r2rml"),
+				},
+			},
+			"ontology": schema.StringAttribute{
+				Optional:    true,
+				Description: "OWL ontology used to reason over the mapped data, in RDF/XML or Turtle syntax.",
+			},
+			"constraint": schema.StringAttribute{
+				Optional:    true,
+				Description: "Constraints of the database, such as keys which are not declared in its schema, in the Ontop constraint file format.",
+			},
+			"jdbc_url": schema.StringAttribute{
+				Required:    true,
+				Description: "JDBC URL of the database, e.g. jdbc:postgresql://db:5432/data.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^jdbc:`), "must be a JDBC URL"),
+				},
+			},
+			"jdbc_driver": schema.StringAttribute{
+				Required:    true,
+				Description: "Class of the JDBC driver, e.g. org.postgresql.Driver. The driver must be installed in GraphDB.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"jdbc_user": schema.StringAttribute{
+				Optional:    true,
+				Description: "User to connect to the database as.",
+			},
+			"jdbc_password": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Password of the database user.",
+			},
+		},
+	}
+}
+
+func (r *OntopRepositoryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when the repository is being destroyed
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	requireFeature(r.server, graphdb.FeatureOntop, path.Root("mapping"), &resp.Diagnostics)
+}
+
+func (r *OntopRepositoryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan OntopRepositoryResourceModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	location := canonicalLocation(plan.Location.ValueString())
+	repos := r.client.InLocation(location)
+	unlock, err := repos.LockRepository(ctx, plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create Repository", fmt.Sprintf("Interrupted while waiting for other operations on the repository. Error: %s", err))
+		return
+	}
+	defer unlock()
+
+	repo := graphdb.Repository{
+		ID:         plan.Name.ValueString(),
+		Type:       "ontop",
+		SesameType: "graphdb:OntopRepository",
+		Location:   location,
+		Params:     map[string]graphdb.RepositoryParam{},
+	}
+	if !plan.Description.IsUnknown() {
+		repo.Title = plan.Description.ValueString()
+	}
+	if err := r.uploadFiles(ctx, repos, plan, nil, repo.Params); err != nil {
+		resp.Diagnostics.AddError("Failed to create Repository", fmt.Sprintf("Failed to upload repository files. Unexpected error %s", err.Error()))
+		return
+	}
+	// GraphDB has no API to delete uploaded files, so they are left on the server if the repository cannot be created
+	if err := repos.CreateRepositoryJSON(ctx, repo); err != nil {
+		uploaded := make([]string, 0, len(repo.Params))
+		for _, param := range repo.Params {
+			uploaded = append(uploaded, param.Value)
+		}
+		sort.Strings(uploaded)
+		tflog.Warn(ctx, "Uploaded repository files are left on the server", map[string]any{"id": repo.ID, "files": uploaded})
+		resp.Diagnostics.AddError("Failed to create Repository",
+			fmt.Sprintf("Failed to create repository. The files uploaded for it are left on the server: %s. Unexpected error %s", strings.Join(uploaded, ", "), err.Error()))
+		return
+	}
+	if err := r.doRead(ctx, location, repo.ID, &plan); err != nil {
+		resp.Diagnostics.AddError("Failed to create Repository", fmt.Sprintf("Failed to retrieve repository after creation. Unexpected error %s", err.Error()))
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *OntopRepositoryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state OntopRepositoryResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	repoName := state.ID.ValueString()
+	tflog.Debug(ctx, "Fetching repository", map[string]any{"id": repoName})
+	location, name := parseRepositoryID(repoName)
+	err := r.doRead(ctx, location, name, &state)
+	if graphdb.IsNotFound(err) {
+		tflog.Warn(ctx, "Repository no longer exists, removing from state", map[string]any{"id": repoName})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read repository", fmt.Sprintf("Unable to read repository. Unexpected error: %s", err))
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *OntopRepositoryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state OntopRepositoryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	repoId := state.ID.ValueString()
+	tflog.Debug(ctx, "Updating repository", map[string]any{"id": repoId})
+	location, name := parseRepositoryID(repoId)
+	repos := r.client.InLocation(location)
+
+	unlock, err := repos.LockRepository(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Interrupted while waiting for other operations on the repository. Error: %s", err))
+		return
+	}
+	defer unlock()
+
+	// GraphDB replaces the whole configuration, so start from the current one and point it to the new files
+	repo, err := repos.GetRepository(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Failed to retrieve repository before update. Unexpected error %s", err.Error()))
+		return
+	}
+	if repo.Params == nil {
+		repo.Params = map[string]graphdb.RepositoryParam{}
+	}
+	// Files whose name and content did not change are not uploaded again, if the repository still points to them
+	unchanged := map[string]bool{}
+	previous := ontopFiles(state)
+	for i, f := range ontopFiles(plan) {
+		if _, ok := repo.Params[f.param]; ok && f.hash() != "" && f.hash() == previous[i].hash() {
+			unchanged[f.param] = true
+		}
+	}
+	params := map[string]graphdb.RepositoryParam{}
+	if err := r.uploadFiles(ctx, repos, plan, unchanged, params); err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Failed to upload repository files. Unexpected error %s", err.Error()))
+		return
+	}
+	for _, name := range []string{ontopMappingParam, ontopOntologyParam, ontopPropertiesParam, ontopConstraintParam} {
+		if unchanged[name] {
+			continue
+		}
+		param, ok := params[name]
+		if !ok {
+			delete(repo.Params, name)
+			continue
+		}
+		if current, ok := repo.Params[name]; ok {
+			param.Label = current.Label
+		}
+		repo.Params[name] = param
+	}
+	if !plan.Description.IsUnknown() && !plan.Description.IsNull() {
+		repo.Title = plan.Description.ValueString()
+	}
+
+	if err := repos.UpdateRepository(ctx, name, repo); err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Failed to update repository. Unexpected error %s", err.Error()))
+		return
+	}
+	if err := r.doRead(ctx, location, name, &plan); err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Failed to retrieve repository after update. Unexpected error %s", err.Error()))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *OntopRepositoryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state OntopRepositoryResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	repoId := state.ID.ValueString()
+	tflog.Debug(ctx, "Deleting repository", map[string]any{"id": repoId})
+	location, name := parseRepositoryID(repoId)
+	repos := r.client.InLocation(location)
+
+	unlock, err := repos.LockRepository(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting repository", fmt.Sprintf("Interrupted while waiting for other operations on the repository. Error: %s", err))
+		return
+	}
+	defer unlock()
+
+	err = repos.DeleteRepository(ctx, name)
+	if graphdb.IsNotFound(err) {
+		tflog.Debug(ctx, "Repository already deleted", map[string]any{"id": repoId})
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting repository", fmt.Sprintf("Could not delete repository. Unexpected error: %s", err.Error()))
+		return
+	}
+}
+
+// ImportState imports a repository by its ID, like graphdb_repository. GraphDB does not return the content of the
+// uploaded files, so the mapping and connection attributes have to be set in the configuration after the import.
+func (r *OntopRepositoryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// doRead refreshes the attributes GraphDB reports. The files are kept as configured, as their content cannot be read back.
+func (r *OntopRepositoryResource) doRead(ctx context.Context, location string, name string, data *OntopRepositoryResourceModel) error {
+	repo, err := r.client.InLocation(location).GetRepository(ctx, name)
+	if err != nil {
+		return err
+	}
+	if repo.Type != "" && repo.Type != "ontop" {
+		tflog.Warn(ctx, "Repository is not an Ontop repository", map[string]any{"id": repo.ID, "type": repo.Type})
+	}
+
+	data.ID = types.StringValue(repositoryID(location, repo.ID))
+	data.Name = types.StringValue(repo.ID)
+	data.Description = types.StringValue(repo.Title)
+	data.Location = refreshLocation(data.Location, location)
+	if data.MappingFormat.IsNull() || data.MappingFormat.IsUnknown() {
+		data.MappingFormat = types.StringValue("obda")
+		if strings.HasSuffix(repo.Params[ontopMappingParam].Value, ontopMappingExtensions["r2rml"]) {
+			data.MappingFormat = types.StringValue("r2rml")
+		}
+	}
+	return nil
+}
+
+// ontopFile is a file of an Ontop repository, uploaded to GraphDB and referred to by a parameter of the repository.
+type ontopFile struct {
+	param    string
+	filename string
+	content  types.String
+}
+
+// ontopFiles returns the files of the repository described by data, in the same order whatever their content.
+func ontopFiles(data OntopRepositoryResourceModel) []ontopFile {
+	name := data.Name.ValueString()
+	return []ontopFile{
+		{ontopMappingParam, name + ontopMappingExtensions[data.MappingFormat.ValueString()], data.Mapping},
+		{ontopOntologyParam, name + ".owl", data.Ontology},
+		{ontopPropertiesParam, name + ".properties", types.StringValue(ontopProperties(data))},
+		{ontopConstraintParam, name + ".txt", data.Constraint},
+	}
+}
+
+// hash identifies the name and content of the file, or is empty if the file is not set.
+func (f ontopFile) hash() string {
+	if f.content.IsNull() || f.content.IsUnknown() {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(f.filename + "\x00" + f.content.ValueString()))
+	return hex.EncodeToString(sum[:])
+}
+
+// uploadFiles uploads the files of the repository described by plan, adding the parameters pointing to them to params.
+// Optional files which are not set, and those of the skipped parameters, are not uploaded.
+func (r *OntopRepositoryResource) uploadFiles(ctx context.Context, repos graphdb.RepositoriesAPI, plan OntopRepositoryResourceModel, skip map[string]bool, params map[string]graphdb.RepositoryParam) error {
+	name := plan.Name.ValueString()
+	for _, f := range ontopFiles(plan) {
+		if f.content.IsNull() || f.content.IsUnknown() || skip[f.param] {
+			continue
+		}
+		tflog.Debug(ctx, "Uploading repository file", map[string]any{"id": name, "file": f.filename})
+		uploaded, err := repos.UploadRepositoryFile(ctx, f.filename, strings.NewReader(f.content.ValueString()))
+		if err != nil {
+			return err
+		}
+		params[f.param] = graphdb.RepositoryParam{Name: f.param, Label: f.param, Value: uploaded}
+	}
+	return nil
+}
+
+// ontopProperties renders the database connection of the repository as the Java properties file read by Ontop.
+func ontopProperties(plan OntopRepositoryResourceModel) string {
+	properties := map[string]types.String{
+		"jdbc.url":      plan.JDBCURL,
+		"jdbc.driver":   plan.JDBCDriver,
+		"jdbc.user":     plan.JDBCUser,
+		"jdbc.password": plan.JDBCPassword,
+	}
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		value := properties[key]
+		if value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		fmt.Fprintf(&b, "%s=%s\n", key, escapeProperty(value.ValueString()))
+	}
+	return b.String()
+}
+
+// propertyEscaper escapes the characters which are special in the values of Java properties files.
+var propertyEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "\f", `\f`)
+
+func escapeProperty(value string) string {
+	value = propertyEscaper.Replace(value)
+	// Leading whitespace is otherwise skipped
+	if strings.HasPrefix(value, " ") {
+		value = `\` + value
+	}
+	return value
+}
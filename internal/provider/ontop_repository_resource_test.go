@@ -0,0 +1,141 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb/graphdbtest"
+)
+
+func TestOntopRepositoryResource(t *testing.T) {
+	server := newTestServer(t)
+	config := func(mapping string) string {
+		return testProviderConfig(server) + fmt.Sprintf(`
+resource "graphdb_ontop_repository" "test" {
+  name          = "Virtual"
+  description   = "Virtual repository"
+  mapping       = %q
+  ontology      = "@prefix owl: <http://www.w3.org/2002/07/owl#> ."
+  jdbc_url      = "jdbc:postgresql://db:5432/data"
+  jdbc_driver   = "org.postgresql.Driver"
+  jdbc_user     = "graphdb"
+  jdbc_password = "s3cr\\et"
+}
+`, mapping)
+	}
+	// checkFile checks the content of the file uploaded for the given parameter of the repository
+	checkFile := func(param string, want string) resource.TestCheckFunc {
+		return func(_ *terraform.State) error {
+			repo, ok := server.Repository("Virtual")
+			if !ok {
+				return fmt.Errorf("Repository was not created")
+			}
+			got, ok := server.UploadedFile(repo.Params[param].Value)
+			if !ok {
+				return fmt.Errorf("No file was uploaded for %s", param)
+			}
+			if !strings.Contains(got, want) {
+				return fmt.Errorf("Unexpected content for %s. Wanted: %s. Got: %s", param, want, got)
+			}
+			return nil
+		}
+	}
+	// uploads records the paths of the files uploaded for the repository, to check that unchanged files are not uploaded again
+	uploads := map[string]string{}
+	recordUploads := func(_ *terraform.State) error {
+		repo, _ := server.Repository("Virtual")
+		for _, param := range []string{"obdaFile", "owlFile", "propertiesFile"} {
+			uploads[param] = repo.Params[param].Value
+		}
+		return nil
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Test Create and Read
+			{
+				Config: config("[MappingDeclaration] @collection [[ mappingId people ]]"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_ontop_repository.test", "id", "Virtual"),
+					resource.TestCheckResourceAttr("graphdb_ontop_repository.test", "mapping_format", "obda"),
+					checkFile("obdaFile", "mappingId people"),
+					checkFile("owlFile", "owl:"),
+					checkFile("propertiesFile", "jdbc.password=s3cr\\\\et\njdbc.url=jdbc:postgresql://db:5432/data\njdbc.user=graphdb\n"),
+					func(_ *terraform.State) error {
+						if repo, _ := server.Repository("Virtual"); repo.Type != "ontop" || repo.Title != "Virtual repository" {
+							return fmt.Errorf("Unexpected repository: %+v", repo)
+						}
+						return nil
+					},
+					recordUploads,
+				),
+			},
+			// Test that changing the mapping updates the repository in place
+			{
+				Config: config("[MappingDeclaration] @collection [[ mappingId cities ]]"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_ontop_repository.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkFile("obdaFile", "mappingId cities"),
+					func(_ *terraform.State) error {
+						if updates := server.RepositoryUpdates("Virtual"); updates != 1 {
+							return fmt.Errorf("Expected a single update. Got: %d", updates)
+						}
+						repo, _ := server.Repository("Virtual")
+						if repo.Params["obdaFile"].Value == uploads["obdaFile"] {
+							return fmt.Errorf("The changed mapping was not uploaded again")
+						}
+						for _, param := range []string{"owlFile", "propertiesFile"} {
+							if got := repo.Params[param].Value; got != uploads[param] {
+								return fmt.Errorf("Unchanged %s was uploaded again. Wanted: %s. Got: %s", param, uploads[param], got)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			// Test import, the files cannot be read back
+			{
+				ResourceName:      "graphdb_ontop_repository.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateVerifyIgnore: []string{
+					"mapping", "ontology", "constraint", "jdbc_url", "jdbc_driver", "jdbc_user", "jdbc_password",
+				},
+			},
+		},
+	})
+}
+
+func TestOntopRepositoryResourceUnsupported(t *testing.T) {
+	server := newTestServer(t, graphdbtest.WithVersion("9.4.0", "free"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig(server) + `
+resource "graphdb_ontop_repository" "test" {
+  name        = "Virtual"
+  mapping     = "[MappingDeclaration] @collection [[ ]]"
+  jdbc_url    = "jdbc:h2:mem:data"
+  jdbc_driver = "org.h2.Driver"
+}
+`,
+				ExpectError: regexp.MustCompile(`Ontop virtual repositories \(requires GraphDB 9.8.0`),
+			},
+		},
+	})
+}
@@ -6,39 +6,83 @@ package provider
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/providervalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
 // Ensure GraphDBProvider satisfies various provider interfaces.
-var _ provider.Provider = &GraphDBProvider{}
+var (
+	_ provider.Provider                     = &GraphDBProvider{}
+	_ provider.ProviderWithConfigValidators = &GraphDBProvider{}
+)
 
 // GraphDBProvider defines the provider implementation.
 type GraphDBProvider struct {
 	version string
 }
 
+// OAuth2Model describes the OAuth2 client credentials configuration.
+type OAuth2Model struct {
+	TokenURL     types.String `tfsdk:"token_url"`
+	ClientID     types.String `tfsdk:"client_id"`
+	ClientSecret types.String `tfsdk:"client_secret"`
+	Scopes       types.List   `tfsdk:"scopes"`
+}
+
 // GraphDBProviderModel describes the provider data model.
 type GraphDBProviderModel struct {
+	Endpoint types.String `tfsdk:"endpoint"`
 	Host     types.String `tfsdk:"host"`
 	Username types.String `tfsdk:"username"`
 	Password types.String `tfsdk:"password"`
 	Port     types.Int64  `tfsdk:"port"`
+	AuthMode types.String `tfsdk:"auth_mode"`
+
+	Endpoints types.List `tfsdk:"endpoints"`
+
+	BearerToken types.String `tfsdk:"bearer_token"`
+	OAuth2      types.Object `tfsdk:"oauth2"`
+
+	Scheme             types.String `tfsdk:"scheme"`
+	CACertificate      types.String `tfsdk:"ca_certificate"`
+	ClientCertificate  types.String `tfsdk:"client_certificate"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+
+	MaxRetries   types.Int64  `tfsdk:"max_retries"`
+	RetryMaxWait types.String `tfsdk:"retry_max_wait"`
+
+	MaxConcurrentRequests types.Int64 `tfsdk:"max_concurrent_requests"`
+
+	CompressUploads       types.Bool   `tfsdk:"compress_uploads"`
+	HTTPCaptureFile       types.String `tfsdk:"http_capture_file"`
+	SkipConnectivityCheck types.Bool   `tfsdk:"skip_connectivity_check"`
 }
 
 const (
 	providerName = "graphdb"
+	defaultPort  = 7200
 )
 
 func (p *GraphDBProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -49,8 +93,30 @@ func (p *GraphDBProvider) Metadata(ctx context.Context, req provider.MetadataReq
 func (p *GraphDBProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
+			"endpoint": schema.StringAttribute{
+				Optional: true,
+				Description: "Base URL of the GraphDB instance, including any path prefix (e.g. https://kg.example.com/graphdb/)." +
+					" Conflicts with host, port and scheme, whether they are set in the configuration or the environment." +
+					" May also be provided via " + EnvEndpoint + " environment variable.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"endpoints": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Base URLs of every node of a GraphDB cluster. Writes are sent to the cluster leader, and requests fail over" +
+					" to another node when one cannot be reached. Requires GraphDB EE 10 or later. Conflicts with endpoint, host, port and scheme." +
+					" May also be provided via " + EnvEndpoints + " environment variable, as a comma-separated list.",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
 			"host": schema.StringAttribute{
 				Optional: true,
+				Description: "Hostname of the GraphDB instance. Shorthand for endpoint, combined with scheme and port." +
+					" May also be provided via " + EnvHost + " environment variable.",
 			},
 			"username": schema.StringAttribute{
 				Optional: true,
@@ -66,18 +132,153 @@ func (p *GraphDBProvider) Schema(ctx context.Context, req provider.SchemaRequest
 				Description: "This is the password for the API connection." +
 					" May also be provided via " + EnvPassword + " environment variable.",
 			},
+			"auth_mode": schema.StringAttribute{
+				Optional: true,
+				Description: "How the username and password are sent to GraphDB." +
+					" Either basic, for HTTP basic authentication on every request," +
+					" token, to log in once and send the resulting GDB token," +
+					" bearer, to send the static bearer_token," +
+					" or oauth2, to obtain bearer tokens using the oauth2 client credentials." +
+					" Defaults to oauth2 or bearer when those are configured, otherwise basic." +
+					" May also be provided via " + EnvAuthMode + " environment variable.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(authModeBasic, authModeToken, authModeBearer, authModeOAuth2),
+				},
+			},
+			"bearer_token": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: "Static bearer token, such as an OpenID Connect access token, sent with every request." +
+					" May also be provided via " + EnvBearerToken + " environment variable.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"oauth2": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "OAuth2 client credentials used to obtain bearer tokens, which are refreshed before they expire.",
+				Attributes: map[string]schema.Attribute{
+					"token_url": schema.StringAttribute{
+						Required:    true,
+						Description: "Token endpoint of the identity provider.",
+					},
+					"client_id": schema.StringAttribute{
+						Required:    true,
+						Description: "OAuth2 client ID.",
+					},
+					"client_secret": schema.StringAttribute{
+						Required:    true,
+						Sensitive:   true,
+						Description: "OAuth2 client secret.",
+					},
+					"scopes": schema.ListAttribute{
+						Optional:    true,
+						ElementType: types.StringType,
+						Description: "Scopes to request with the token.",
+					},
+				},
+			},
 			"port": schema.Int64Attribute{
 				Optional: true,
-				Description: "This is the tcp port for the API connection." +
+				Description: "This is the tcp port for the API connection. Defaults to 7200." +
 					" May also be provided via " + EnvPort + " environment variable.",
 				Validators: []validator.Int64{
 					int64validator.Between(1, 65535),
 				},
 			},
+			"scheme": schema.StringAttribute{
+				Optional: true,
+				Description: "URL scheme used to connect to GraphDB, either http or https. Defaults to http." +
+					" May also be provided via " + EnvScheme + " environment variable.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("http", "https"),
+				},
+			},
+			"ca_certificate": schema.StringAttribute{
+				Optional: true,
+				Description: "PEM encoded CA bundle used to verify the GraphDB server certificate, in addition to the system roots." +
+					" May also be provided via " + EnvCACertificate + " environment variable.",
+			},
+			"client_certificate": schema.StringAttribute{
+				Optional: true,
+				Description: "PEM encoded client certificate used for mutual TLS authentication." +
+					" May also be provided via " + EnvClientCertificate + " environment variable.",
+			},
+			"client_key": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				Description: "PEM encoded private key for the client certificate." +
+					" May also be provided via " + EnvClientKey + " environment variable.",
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				Optional: true,
+				Description: "Disable verification of the GraphDB server certificate. Only use this for testing." +
+					" May also be provided via " + EnvInsecureSkipVerify + " environment variable.",
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional: true,
+				Description: "Maximum number of times a request is retried after a transient failure, such as GraphDB restarting." +
+					" Non-idempotent requests are only retried when the server cannot have processed them. Defaults to 3." +
+					" May also be provided via " + EnvMaxRetries + " environment variable.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"retry_max_wait": schema.StringAttribute{
+				Optional: true,
+				Description: "Maximum time to wait between retries, as a duration string (e.g. 30s). Defaults to 30s." +
+					" May also be provided via " + EnvRetryMaxWait + " environment variable.",
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional: true,
+				Description: "Maximum number of requests sent to GraphDB at once. Terraform performs up to 10 operations in parallel," +
+					" so lower this if GraphDB struggles under load. Defaults to unlimited." +
+					" May also be provided via " + EnvMaxConcurrentRequests + " environment variable.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"compress_uploads": schema.BoolAttribute{
+				Optional: true,
+				Description: "Compress uploaded files, such as repository configurations, using gzip." +
+					" GraphDB, or a proxy in front of it, must accept compressed requests. Defaults to false." +
+					" May also be provided via " + EnvCompressUploads + " environment variable.",
+			},
+			"http_capture_file": schema.StringAttribute{
+				Optional: true,
+				Description: "Path of a file to record every request sent to GraphDB, and its response, in the HTTP Archive (HAR) format." +
+					" Useful when reporting issues. Credentials are redacted, but request and response bodies are truncated rather than removed." +
+					" May also be provided via " + EnvHTTPCaptureFile + " environment variable.",
+			},
+			"skip_connectivity_check": schema.BoolAttribute{
+				Optional: true,
+				Description: "Skip connecting to GraphDB when the provider is configured, which otherwise checks that the server is reachable" +
+					" and accepts the credentials, and detects its version. Useful for plan-only workflows without access to the server." +
+					" Since the version is not detected, the provider then cannot reject features the server does not support," +
+					" and leaves that to the server when the configuration is applied." +
+					" May also be provided via " + EnvSkipConnectivityCheck + " environment variable.",
+			},
 		},
 	}
 }
 
+func (p *GraphDBProvider) ConfigValidators(ctx context.Context) []provider.ConfigValidator {
+	return []provider.ConfigValidator{
+		providervalidator.Conflicting(path.MatchRoot("endpoint"), path.MatchRoot("host")),
+		providervalidator.Conflicting(path.MatchRoot("endpoint"), path.MatchRoot("port")),
+		providervalidator.Conflicting(path.MatchRoot("endpoint"), path.MatchRoot("scheme")),
+		providervalidator.Conflicting(path.MatchRoot("endpoints"), path.MatchRoot("endpoint")),
+		providervalidator.Conflicting(path.MatchRoot("endpoints"), path.MatchRoot("host")),
+		providervalidator.Conflicting(path.MatchRoot("endpoints"), path.MatchRoot("port")),
+		providervalidator.Conflicting(path.MatchRoot("endpoints"), path.MatchRoot("scheme")),
+		providervalidator.Conflicting(path.MatchRoot("bearer_token"), path.MatchRoot("oauth2")),
+		providervalidator.RequiredTogether(
+			path.MatchRoot("client_certificate"),
+			path.MatchRoot("client_key"),
+		),
+	}
+}
+
 func (p *GraphDBProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	tflog.Info(ctx, "Initializing GraphDB provider")
 	var config GraphDBProviderModel
@@ -94,8 +295,16 @@ func (p *GraphDBProvider) Configure(ctx context.Context, req provider.ConfigureR
 	instructionUnknownMessage := " Either target apply the source of the value first, " +
 		"set the value statically in the configuration, or use the %s environment variable."
 
+	if config.Endpoint.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("endpoint"), "Unknown GraphDB Endpoint", fmt.Sprintf("%s for the GraphDB Endpoint. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvEndpoint)))
+	}
+
+	if config.Endpoints.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("endpoints"), "Unknown GraphDB Endpoints", fmt.Sprintf("%s for the GraphDB Endpoints. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvEndpoints)))
+	}
+
 	if config.Host.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(path.Root("address"), "Unknown GraphDB Host", fmt.Sprintf("%s for the GraphDB Host. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvHost)))
+		resp.Diagnostics.AddAttributeError(path.Root("host"), "Unknown GraphDB Host", fmt.Sprintf("%s for the GraphDB Host. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvHost)))
 	}
 
 	if config.Port.IsUnknown() {
@@ -110,43 +319,157 @@ func (p *GraphDBProvider) Configure(ctx context.Context, req provider.ConfigureR
 		resp.Diagnostics.AddAttributeError(path.Root("password"), "Unknown GraphDB Password", fmt.Sprintf("%s for the GraphDB Password. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvPassword)))
 	}
 
+	if config.AuthMode.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("auth_mode"), "Unknown GraphDB Auth Mode", fmt.Sprintf("%s for the GraphDB Auth Mode. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvAuthMode)))
+	}
+
+	if config.BearerToken.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("bearer_token"), "Unknown GraphDB Bearer Token", fmt.Sprintf("%s for the GraphDB Bearer Token. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvBearerToken)))
+	}
+
+	if config.OAuth2.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("oauth2"), "Unknown GraphDB OAuth2 configuration", unknownValueErrorMessage+"for the GraphDB OAuth2 configuration. Either target apply the source of the value first or set the value statically in the configuration.")
+	}
+
+	if config.MaxRetries.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("max_retries"), "Unknown GraphDB Max Retries", fmt.Sprintf("%s for the GraphDB Max Retries. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvMaxRetries)))
+	}
+
+	if config.RetryMaxWait.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("retry_max_wait"), "Unknown GraphDB Retry Max Wait", fmt.Sprintf("%s for the GraphDB Retry Max Wait. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvRetryMaxWait)))
+	}
+
+	if config.MaxConcurrentRequests.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("max_concurrent_requests"), "Unknown GraphDB Max Concurrent Requests", fmt.Sprintf("%s for the GraphDB Max Concurrent Requests. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvMaxConcurrentRequests)))
+	}
+
+	if config.CompressUploads.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("compress_uploads"), "Unknown GraphDB Compress Uploads", fmt.Sprintf("%s for the GraphDB Compress Uploads. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvCompressUploads)))
+	}
+
+	if config.HTTPCaptureFile.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("http_capture_file"), "Unknown GraphDB HTTP Capture File", fmt.Sprintf("%s for the GraphDB HTTP Capture File. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvHTTPCaptureFile)))
+	}
+
+	if config.SkipConnectivityCheck.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("skip_connectivity_check"), "Unknown GraphDB Skip Connectivity Check", fmt.Sprintf("%s for the GraphDB Skip Connectivity Check. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvSkipConnectivityCheck)))
+	}
+
+	if config.Scheme.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("scheme"), "Unknown GraphDB Scheme", fmt.Sprintf("%s for the GraphDB Scheme. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvScheme)))
+	}
+
+	if config.CACertificate.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("ca_certificate"), "Unknown GraphDB CA Certificate", fmt.Sprintf("%s for the GraphDB CA Certificate. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvCACertificate)))
+	}
+
+	if config.ClientCertificate.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("client_certificate"), "Unknown GraphDB Client Certificate", fmt.Sprintf("%s for the GraphDB Client Certificate. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvClientCertificate)))
+	}
+
+	if config.ClientKey.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("client_key"), "Unknown GraphDB Client Key", fmt.Sprintf("%s for the GraphDB Client Key. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvClientKey)))
+	}
+
+	if config.InsecureSkipVerify.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(path.Root("insecure_skip_verify"), "Unknown GraphDB Insecure Skip Verify", fmt.Sprintf("%s for the GraphDB Insecure Skip Verify. %s", unknownValueErrorMessage, fmt.Sprintf(instructionUnknownMessage, EnvInsecureSkipVerify)))
+	}
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	host := os.Getenv(EnvHost)
-	if !config.Host.IsNull() {
-		host = config.Host.ValueString()
+	var endpoint *url.URL
+	var clusterEndpoints []string
+	cluster := !config.Endpoints.IsNull() || (config.Endpoint.IsNull() && config.Host.IsNull() && os.Getenv(EnvEndpoints) != "")
+	if cluster {
+		nodes := p.resolveClusterEndpoints(ctx, config, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		endpoint = nodes[0]
+		for _, node := range nodes[1:] {
+			clusterEndpoints = append(clusterEndpoints, node.String())
+		}
+	} else {
+		endpoint = p.resolveEndpoint(config, &resp.Diagnostics)
 	}
-	if host == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("address"),
-			"Missing GraphDB address target",
-			"The provider cannot create the GraphDB client as there is a missing or empty value for the GraphDB address."+
-				" Set the value in the configuration or use the "+EnvHost+" environment variable."+
-				" If either is already set, ensure the value is not empty.",
-		)
-
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	client := NewClient(host)
+	tlsConfig := graphdb.TLSConfig{
+		CACertificate:     stringValueOrEnv(config.CACertificate, EnvCACertificate),
+		ClientCertificate: stringValueOrEnv(config.ClientCertificate, EnvClientCertificate),
+		ClientKey:         stringValueOrEnv(config.ClientKey, EnvClientKey),
+	}
+	tlsConfig.Insecure = boolValueOrEnv(config.InsecureSkipVerify, EnvInsecureSkipVerify, path.Root("insecure_skip_verify"), &resp.Diagnostics)
 
-	if !config.Port.IsNull() {
-		client.WithPort(int(config.Port.ValueInt64()))
-	} else if v := os.Getenv(EnvPort); v != "" {
+	opts := []graphdb.Option{graphdb.WithTLS(tlsConfig)}
+
+	maxRetries := graphdb.DefaultMaxRetries
+	if !config.MaxRetries.IsNull() {
+		maxRetries = int(config.MaxRetries.ValueInt64())
+	} else if v := os.Getenv(EnvMaxRetries); v != "" {
 		d, err := strconv.Atoi(v)
-		if err != nil {
+		if err != nil || d < 0 {
 			resp.Diagnostics.AddAttributeWarning(
-				path.Root("port"),
-				"Failed to parse "+EnvPort,
-				fmt.Sprintf("Error to parse value in "+EnvPort+" environment variable: %s\n"+
-					"So the variable is not used", err),
+				path.Root("max_retries"),
+				"Failed to parse "+EnvMaxRetries,
+				fmt.Sprintf("Error to parse value in "+EnvMaxRetries+" environment variable: %s\n"+
+					"So the variable is not used", v),
+			)
+		} else {
+			maxRetries = d
+		}
+	}
+
+	retryMaxWait := graphdb.DefaultRetryMaxWait
+	if v := stringValueOrEnv(config.RetryMaxWait, EnvRetryMaxWait); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("retry_max_wait"),
+				"Invalid GraphDB retry max wait",
+				fmt.Sprintf("The retry max wait must be a positive duration such as 30s, got: %s", v),
+			)
+			return
+		}
+		retryMaxWait = d
+	}
+	opts = append(opts, graphdb.WithRetry(maxRetries, retryMaxWait))
+
+	if len(clusterEndpoints) > 0 {
+		opts = append(opts, graphdb.WithClusterEndpoints(clusterEndpoints...))
+	}
+
+	maxConcurrentRequests := 0
+	if !config.MaxConcurrentRequests.IsNull() {
+		maxConcurrentRequests = int(config.MaxConcurrentRequests.ValueInt64())
+	} else if v := os.Getenv(EnvMaxConcurrentRequests); v != "" {
+		d, err := strconv.Atoi(v)
+		if err != nil || d < 1 {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("max_concurrent_requests"),
+				"Failed to parse "+EnvMaxConcurrentRequests,
+				fmt.Sprintf("Error to parse value in "+EnvMaxConcurrentRequests+" environment variable: %s\n"+
+					"So the variable is not used", v),
 			)
 		} else {
-			client.WithPort(d)
+			maxConcurrentRequests = d
 		}
 	}
+	if maxConcurrentRequests > 0 {
+		opts = append(opts, graphdb.WithMaxConcurrentRequests(maxConcurrentRequests))
+	}
+
+	if boolValueOrEnv(config.CompressUploads, EnvCompressUploads, path.Root("compress_uploads"), &resp.Diagnostics) {
+		opts = append(opts, graphdb.WithUploadCompression())
+	}
+
+	if captureFile := stringValueOrEnv(config.HTTPCaptureFile, EnvHTTPCaptureFile); captureFile != "" {
+		opts = append(opts, graphdb.WithHTTPCapture(captureFile))
+	}
 
 	var username = ""
 
@@ -156,7 +479,6 @@ func (p *GraphDBProvider) Configure(ctx context.Context, req provider.ConfigureR
 	} else if v := os.Getenv(EnvUsername); v != "" {
 		username = v
 	}
-	client.WithUsername(username)
 
 	var password = ""
 	if !config.Password.IsNull() {
@@ -164,20 +486,305 @@ func (p *GraphDBProvider) Configure(ctx context.Context, req provider.ConfigureR
 	} else if v := os.Getenv(EnvPassword); v != "" {
 		password = v
 	}
-	client.WithPassword(password)
 
-	ctx = tflog.SetField(ctx, "graphdb_host", host)
+	bearerToken := stringValueOrEnv(config.BearerToken, EnvBearerToken)
+
+	authMode := stringValueOrEnv(config.AuthMode, EnvAuthMode)
+	if authMode == "" {
+		switch {
+		case !config.OAuth2.IsNull():
+			authMode = authModeOAuth2
+		case bearerToken != "":
+			authMode = authModeBearer
+		default:
+			authMode = authModeBasic
+		}
+	}
+
+	switch authMode {
+	case authModeBasic:
+		opts = append(opts, graphdb.WithBasicAuth(username, password))
+	case authModeToken:
+		opts = append(opts, graphdb.WithTokenAuth(username, password))
+	case authModeBearer:
+		if bearerToken == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("bearer_token"),
+				"Missing GraphDB bearer token",
+				"The bearer authentication mode requires a bearer token."+
+					" Set the value in the configuration or use the "+EnvBearerToken+" environment variable.",
+			)
+			return
+		}
+		opts = append(opts, graphdb.WithBearerToken(bearerToken))
+	case authModeOAuth2:
+		if config.OAuth2.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("oauth2"),
+				"Missing GraphDB OAuth2 configuration",
+				"The oauth2 authentication mode requires the oauth2 attribute to be set.",
+			)
+			return
+		}
+		var oauth OAuth2Model
+		resp.Diagnostics.Append(config.OAuth2.As(ctx, &oauth, basetypes.ObjectAsOptions{})...)
+		var scopes []string
+		resp.Diagnostics.Append(oauth.Scopes.ElementsAs(ctx, &scopes, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		opts = append(opts, graphdb.WithOAuth2(clientcredentials.Config{
+			TokenURL:     oauth.TokenURL.ValueString(),
+			ClientID:     oauth.ClientID.ValueString(),
+			ClientSecret: oauth.ClientSecret.ValueString(),
+			Scopes:       scopes,
+		}))
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("auth_mode"),
+			"Invalid GraphDB authentication mode",
+			fmt.Sprintf("The GraphDB authentication mode must be one of %s, %s, %s or %s, got: %s", authModeBasic, authModeToken, authModeBearer, authModeOAuth2, authMode),
+		)
+		return
+	}
+
+	client, err := graphdb.NewClient(endpoint.String(), opts...)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to create GraphDB client", err.Error())
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "graphdb_endpoint", endpoint.String())
 	ctx = tflog.SetField(ctx, "graphdb_username", username)
+	ctx = tflog.SetField(ctx, "graphdb_auth_mode", authMode)
 	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, "graphdb_password", password)
 	tflog.Info(ctx, "Created client")
 
+	skipConnectivityCheck := boolValueOrEnv(config.SkipConnectivityCheck, EnvSkipConnectivityCheck, path.Root("skip_connectivity_check"), &resp.Diagnostics)
+
+	if skipConnectivityCheck {
+		tflog.Info(ctx, "Skipping GraphDB connectivity check")
+	} else {
+		credentials := authMode != authModeBasic || username != ""
+		if !p.checkConnectivity(ctx, client, endpoint, credentials, &resp.Diagnostics) {
+			return
+		}
+
+		info, err := client.DetectServer(ctx)
+		if err != nil {
+			resp.Diagnostics.AddWarning(
+				"Unable to detect GraphDB version",
+				fmt.Sprintf("The provider could not determine the version and edition of the GraphDB server,"+
+					" so it cannot check that the server supports the features required by your configuration: %s", err),
+			)
+		} else {
+			tflog.Info(ctx, "Detected GraphDB server", map[string]any{"graphdb_version": info.Version.String(), "graphdb_edition": string(info.Edition)})
+		}
+
+		if cluster {
+			requireFeature(client, graphdb.FeatureCluster, path.Root("endpoints"), &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+	}
+
 	resp.DataSourceData = client
 	resp.ResourceData = client
 }
 
+// resolveClusterEndpoints parses the base URLs of the nodes of a cluster, from either the endpoints attribute
+// or the corresponding environment variable.
+func (p *GraphDBProvider) resolveClusterEndpoints(ctx context.Context, config GraphDBProviderModel, diags *diag.Diagnostics) []*url.URL {
+	var raw []string
+	if !config.Endpoints.IsNull() {
+		diags.Append(config.Endpoints.ElementsAs(ctx, &raw, false)...)
+		if diags.HasError() {
+			return nil
+		}
+	} else {
+		for _, e := range strings.Split(os.Getenv(EnvEndpoints), ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				raw = append(raw, e)
+			}
+		}
+	}
+
+	nodes := make([]*url.URL, 0, len(raw))
+	for _, e := range raw {
+		node, err := graphdb.ParseEndpoint(e)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("endpoints"),
+				"Invalid GraphDB endpoint",
+				fmt.Sprintf("Every GraphDB endpoint must be an absolute http or https URL, got: %s. Error: %s", e, err),
+			)
+			return nil
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 0 {
+		diags.AddAttributeError(
+			path.Root("endpoints"),
+			"Missing GraphDB endpoints",
+			"At least one GraphDB endpoint is required.",
+		)
+	}
+	return nodes
+}
+
+// checkConnectivity verifies that the server is reachable and accepts the configured credentials,
+// reporting a diagnostic for each kind of failure. It returns false if the provider cannot be used.
+func (p *GraphDBProvider) checkConnectivity(ctx context.Context, client *graphdb.Client, endpoint *url.URL, credentials bool, diags *diag.Diagnostics) bool {
+	enabled, err := client.SecurityEnabled(ctx)
+	if err == nil && enabled {
+		_, err = client.GetAuthenticatedUser(ctx)
+	}
+
+	switch {
+	case err == nil:
+	case graphdb.IsUnauthorized(err):
+		diags.AddError(
+			"Invalid GraphDB credentials",
+			fmt.Sprintf("GraphDB at %s rejected the configured credentials."+
+				" Check the username and password, or the bearer token or OAuth2 client, for the selected auth_mode: %s", endpoint, err),
+		)
+		return false
+	case graphdb.IsTLSError(err):
+		diags.AddError(
+			"Unable to establish a secure connection to GraphDB",
+			fmt.Sprintf("The TLS handshake with %s failed. Check that the server uses https,"+
+				" and that ca_certificate trusts its certificate: %s", endpoint, err),
+		)
+		return false
+	case graphdb.IsUnreachable(err):
+		diags.AddError(
+			"Unable to connect to GraphDB",
+			fmt.Sprintf("Could not connect to %s. Check the endpoint, and that the server is running and reachable."+
+				" Set skip_connectivity_check to plan without connecting to the server: %s", endpoint, err),
+		)
+		return false
+	default:
+		diags.AddError(
+			"Unable to connect to GraphDB",
+			fmt.Sprintf("Unexpected error checking the connection to %s: %s", endpoint, err),
+		)
+		return false
+	}
+
+	if !enabled && credentials {
+		diags.AddWarning(
+			"GraphDB security is disabled",
+			fmt.Sprintf("Security is disabled on %s, so the configured credentials are ignored and every request is allowed."+
+				" Users managed by this provider cannot log in until security is enabled.", endpoint),
+		)
+	}
+	return true
+}
+
+// resolveEndpoint determines the base URL of the GraphDB instance, either from the endpoint attribute
+// or from the host, port and scheme shorthand.
+func (p *GraphDBProvider) resolveEndpoint(config GraphDBProviderModel, diags *diag.Diagnostics) *url.URL {
+	if raw := stringValueOrEnv(config.Endpoint, EnvEndpoint); raw != "" {
+		// The configuration validators only reject the shorthand attributes, so check the environment variables as well
+		if conflicts := shorthandSources(config, config.Endpoint.IsNull()); len(conflicts) > 0 {
+			diags.AddAttributeError(
+				path.Root("endpoint"),
+				"Conflicting GraphDB address",
+				fmt.Sprintf("The GraphDB endpoint cannot be combined with the host, port and scheme shorthand."+
+					" Unset either the endpoint or the %s.", strings.Join(conflicts, ", ")),
+			)
+			return nil
+		}
+		endpoint, err := graphdb.ParseEndpoint(raw)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("endpoint"),
+				"Invalid GraphDB endpoint",
+				fmt.Sprintf("The GraphDB endpoint must be an absolute http or https URL, got: %s. Error: %s", raw, err),
+			)
+			return nil
+		}
+		return endpoint
+	}
+
+	host := stringValueOrEnv(config.Host, EnvHost)
+	if host == "" {
+		diags.AddAttributeError(
+			path.Root("host"),
+			"Missing GraphDB address target",
+			"The provider cannot create the GraphDB client as there is a missing or empty value for the GraphDB address."+
+				" Set the endpoint or host value in the configuration or use the "+EnvEndpoint+" or "+EnvHost+" environment variable."+
+				" If either is already set, ensure the value is not empty.",
+		)
+		return nil
+	}
+
+	scheme := stringValueOrEnv(config.Scheme, EnvScheme)
+	if scheme == "" {
+		scheme = "http"
+	}
+	if scheme != "http" && scheme != "https" {
+		diags.AddAttributeError(
+			path.Root("scheme"),
+			"Invalid GraphDB scheme",
+			fmt.Sprintf("The GraphDB scheme must be either http or https, got: %s", scheme),
+		)
+		return nil
+	}
+
+	port := defaultPort
+	if !config.Port.IsNull() {
+		port = int(config.Port.ValueInt64())
+	} else if v := os.Getenv(EnvPort); v != "" {
+		d, err := strconv.Atoi(v)
+		if err != nil {
+			diags.AddAttributeWarning(
+				path.Root("port"),
+				"Failed to parse "+EnvPort,
+				fmt.Sprintf("Error to parse value in "+EnvPort+" environment variable: %s\n"+
+					"So the variable is not used", err),
+			)
+		} else {
+			port = d
+		}
+	}
+
+	return &url.URL{
+		Scheme: scheme,
+		Host:   net.JoinHostPort(host, strconv.Itoa(port)),
+		Path:   "/",
+	}
+}
+
+// shorthandSources returns where the host, port and scheme shorthand is set, in the environment and, if attributes
+// is set, in the configuration.
+func shorthandSources(config GraphDBProviderModel, attributes bool) []string {
+	var sources []string
+	for _, shorthand := range []struct {
+		attribute string
+		set       bool
+		env       string
+	}{
+		{"host", !config.Host.IsNull(), EnvHost},
+		{"port", !config.Port.IsNull(), EnvPort},
+		{"scheme", !config.Scheme.IsNull(), EnvScheme},
+	} {
+		if attributes && shorthand.set {
+			sources = append(sources, shorthand.attribute+" attribute")
+		}
+		if os.Getenv(shorthand.env) != "" {
+			sources = append(sources, shorthand.env+" environment variable")
+		}
+	}
+	return sources
+}
+
 func (p *GraphDBProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewRepositoryResource,
+		NewOntopRepositoryResource,
+		NewFedXRepositoryResource,
 		NewUserResource,
 	}
 }
@@ -203,7 +810,7 @@ func unexpectedDataSourceConfigureType(
 	resp.Diagnostics.AddError(
 		"Unexpected Data Source Configure Type",
 		fmt.Sprintf(
-			"Expected *Client, got: %T. Please report this issue to the provider developers.",
+			"Expected *graphdb.Client, got: %T. Please report this issue to the provider developers.",
 			req.ProviderData,
 		),
 	)
@@ -4,8 +4,19 @@
 package provider
 
 import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb/graphdbtest"
 )
 
 const (
@@ -25,3 +36,191 @@ provider "graphdb" {
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
 	"graphdb": providerserver.NewProtocol6WithError(New("test")()),
 }
+
+// newTestServer starts an in-process fake GraphDB server, with security enabled, for the duration of the test.
+// Tests using it still need the Terraform CLI. They are skipped when it is not installed, unless TF_ACC is set,
+// in which case a missing CLI fails the test so that CI and `make test` cannot pass without running them.
+func newTestServer(t *testing.T, opts ...graphdbtest.Option) *graphdbtest.Server {
+	t.Helper()
+	if _, err := exec.LookPath("terraform"); err != nil && os.Getenv("TF_ACC_TERRAFORM_PATH") == "" {
+		if os.Getenv("TF_ACC") != "" {
+			t.Fatal("Terraform CLI not found, set TF_ACC_TERRAFORM_PATH or add terraform to the PATH")
+		}
+		t.Skip("Terraform CLI not found, set TF_ACC_TERRAFORM_PATH or add terraform to the PATH")
+	}
+
+	server := graphdbtest.NewServer(append([]graphdbtest.Option{graphdbtest.WithSecurity()}, opts...)...)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// testProviderConfig returns a provider block connected to the given fake server.
+func testProviderConfig(server *graphdbtest.Server) string {
+	return fmt.Sprintf(`
+provider "graphdb" {
+  endpoint = %q
+  username = %q
+  password = %q
+}
+`, server.URL, graphdbtest.AdminUsername, graphdbtest.AdminPassword)
+}
+
+// testDataPath returns the absolute path of a file in the testdata directory,
+// since Terraform runs the configuration from a temporary directory.
+func testDataPath(t *testing.T, name string) string {
+	t.Helper()
+	p, err := filepath.Abs(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestResolveEndpointConflicts(t *testing.T) {
+	for _, env := range []string{EnvEndpoint, EnvHost, EnvPort, EnvScheme} {
+		t.Setenv(env, "")
+	}
+	p := &GraphDBProvider{}
+	config := GraphDBProviderModel{
+		Endpoint: types.StringValue("https://kg.example.com/graphdb/"),
+		Host:     types.StringNull(),
+		Port:     types.Int64Null(),
+		Scheme:   types.StringNull(),
+	}
+
+	var diags diag.Diagnostics
+	if endpoint := p.resolveEndpoint(config, &diags); diags.HasError() || endpoint.String() != "https://kg.example.com/graphdb/" {
+		t.Fatalf("Unexpected endpoint %s. Errors: %v", endpoint, diags)
+	}
+
+	// The shorthand environment variables conflict with the endpoint, like the attributes do
+	t.Setenv(EnvScheme, "http")
+	diags = nil
+	if p.resolveEndpoint(config, &diags); !diags.HasError() {
+		t.Fatal("Expected the endpoint to conflict with " + EnvScheme)
+	}
+
+	// And so does the endpoint environment variable with the shorthand attributes
+	t.Setenv(EnvScheme, "")
+	t.Setenv(EnvEndpoint, "https://kg.example.com/graphdb/")
+	config.Endpoint = types.StringNull()
+	config.Host = types.StringValue("localhost")
+	diags = nil
+	if p.resolveEndpoint(config, &diags); !diags.HasError() {
+		t.Fatal("Expected " + EnvEndpoint + " to conflict with the host attribute")
+	}
+}
+
+func TestProviderInvalidCredentials(t *testing.T) {
+	server := newTestServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "graphdb" {
+  endpoint = %q
+  username = "admin"
+  password = "wrong"
+  auth_mode = "token"
+}
+
+data "graphdb_users" "users" {}
+`, server.URL),
+				ExpectError: regexp.MustCompile("Invalid GraphDB credentials"),
+			},
+		},
+	})
+}
+
+func TestProviderUnreachable(t *testing.T) {
+	server := newTestServer(t)
+	server.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "graphdb" {
+  endpoint = %q
+  max_retries = 0
+}
+
+data "graphdb_users" "users" {}
+`, server.URL),
+				ExpectError: regexp.MustCompile("Unable to connect to GraphDB"),
+			},
+			// Planning without contacting the server
+			{
+				Config: fmt.Sprintf(`
+provider "graphdb" {
+  endpoint = %q
+  skip_connectivity_check = true
+}
+
+resource "graphdb_user" "test" {
+  username = "TestUser"
+  password = "SuperSecret"
+  role = "user"
+}
+`, server.URL),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestProviderClusterFailover(t *testing.T) {
+	down := newTestServer(t)
+	down.Close()
+	server := newTestServer(t, graphdbtest.WithVersion(graphdbtest.DefaultVersion, "ee"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "graphdb" {
+  endpoints = [%q, %q]
+  username = %q
+  password = %q
+}
+
+resource "graphdb_user" "test" {
+  username = "TestUser"
+  password = "SuperSecret"
+  role = "user"
+}
+`, down.URL, server.URL, graphdbtest.AdminUsername, graphdbtest.AdminPassword),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_user.test", "username", "TestUser"),
+				),
+			},
+		},
+	})
+}
+
+func TestProviderClusterRequiresEnterprise(t *testing.T) {
+	server := newTestServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "graphdb" {
+  endpoints = [%q]
+  username = %q
+  password = %q
+}
+
+data "graphdb_users" "users" {}
+`, server.URL, graphdbtest.AdminUsername, graphdbtest.AdminPassword),
+				ExpectError: regexp.MustCompile("Unsupported GraphDB feature"),
+			},
+		},
+	})
+}
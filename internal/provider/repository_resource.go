@@ -5,32 +5,83 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
+	"github.com/nickrobison/terraform-provider-graphdb/internal/turtle"
 )
 
 var (
 	_ resource.Resource                = &RepositoryResource{}
 	_ resource.ResourceWithImportState = &RepositoryResource{}
+	_ resource.ResourceWithModifyPlan  = &RepositoryResource{}
+
+	_ resource.ResourceWithConfigValidators = &RepositoryResource{}
+	_ resource.ResourceWithValidateConfig   = &RepositoryResource{}
+)
+
+// repositoryTypeFeatures are the repository types which are only available on some GraphDB servers.
+var repositoryTypeFeatures = map[string]graphdb.Feature{
+	"graphdb:FedXRepository":  graphdb.FeatureFedX,
+	"graphdb:OntopRepository": graphdb.FeatureOntop,
+}
+
+// immutableRepositoryParams are the parameters which determine how a repository is stored, so that GraphDB cannot
+// change them without rebuilding the repository. They are keyed as in the JSON form of a repository.
+var immutableRepositoryParams = []string{
+	"storageFolder",
+	"entityIdSize",
+	"ruleset",
+	"disableSameAs",
+	"enableContextIndex",
+	"repositoryType",
+}
+
+// Default durations of the timeouts attribute, how often the repository state is polled while waiting for it, and
+// how long reading a repository which did not start in time may take.
+var (
+	defaultRepositoryCreateTimeout = 10 * time.Minute
+	defaultRepositoryUpdateTimeout = 10 * time.Minute
+	repositoryPollInterval         = 2 * time.Second
+	repositoryReadTimeout          = 30 * time.Second
 )
 
 type RepositoryResource struct {
-	client *Client
+	client graphdb.RepositoriesAPI
+	server graphdb.InfoAPI
 }
 
 type RepositoryResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	Config      types.String `tfsdk:"config"`
-	Description types.String `tfsdk:"description"`
-	Location    types.String `tfsdk:"location"`
-	Type        types.String `tfsdk:"type"`
+	ID                 types.String   `tfsdk:"id"`
+	Name               types.String   `tfsdk:"name"`
+	Config             types.String   `tfsdk:"config"`
+	ConfigJSON         types.String   `tfsdk:"config_json"`
+	Settings           types.Object   `tfsdk:"settings"`
+	Description        types.String   `tfsdk:"description"`
+	Location           types.String   `tfsdk:"location"`
+	Type               types.String   `tfsdk:"type"`
+	State              types.String   `tfsdk:"state"`
+	RestartTriggers    types.Map      `tfsdk:"restart_triggers"`
+	DeletionProtection types.Bool     `tfsdk:"deletion_protection"`
+	Timeouts           timeouts.Value `tfsdk:"timeouts"`
 }
 
 func NewRepositoryResource() resource.Resource {
@@ -42,13 +93,14 @@ func (r *RepositoryResource) Configure(ctx context.Context, req resource.Configu
 		return
 	}
 
-	client, ok := req.ProviderData.(*Client)
+	client, ok := req.ProviderData.(graphdb.RepositoriesAPI)
 	if !ok {
 		ProviderDataError(req.ProviderData, &resp.Diagnostics)
 		return
 	}
 
 	r.client = client
+	r.server, _ = req.ProviderData.(graphdb.InfoAPI)
 }
 
 func (r *RepositoryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -61,30 +113,223 @@ func (r *RepositoryResource) Schema(ctx context.Context, req resource.SchemaRequ
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed:    true,
-				Description: "An identifier for the resource",
+				Description: "An identifier for the resource, the name prefixed with the location for repositories in a remote location",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"name": schema.StringAttribute{
 				Required:    true,
 				Description: "Repository name",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"description": schema.StringAttribute{
 				Optional:    true,
-				Description: "Repository Description",
+				Computed:    true,
+				Description: "Repository Description. Defaults to the label in the configuration file.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"config": schema.StringAttribute{
-				Optional:    true,
-				Description: "Configuration file in Turtle syntax",
+				Optional: true,
+				Description: "Configuration file in Turtle syntax, for settings which are not supported by the settings attribute. " +
+					"Changing the repository type or a parameter affecting storage (" + strings.Join(immutableParamNames(), ", ") +
+					") recreates the repository, other changes are applied in place and parameters removed from it are reset to their defaults.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(configRequiresReplace,
+						"Changing the repository type or a parameter affecting storage recreates the repository.",
+						"Changing the repository type or a parameter affecting storage recreates the repository."),
+				},
 			},
+			"config_json": schema.StringAttribute{
+				Optional: true,
+				Description: "Configuration in the JSON form accepted by the GraphDB REST API, e.g. built with jsonencode, as an alternative to config." +
+					" Parameters are keyed as GraphDB returns them, e.g. entityIdSize rather than entity-id-size, and those it does not set use the GraphDB defaults." +
+					" Only the settings it sets are compared with the live repository. Changing the repository type or a parameter affecting storage recreates the repository, other changes are applied in place.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(configJSONRequiresReplace,
+						"Changing the repository type or a parameter affecting storage recreates the repository.",
+						"Changing the repository type or a parameter affecting storage recreates the repository."),
+				},
+			},
+			"settings": repositorySettingsSchema(),
 			"location": schema.StringAttribute{
-				Computed:    true,
-				Description: "Repository External URL"},
+				Optional: true,
+				Computed: true,
+				Description: "URL of a remote location attached to GraphDB to manage the repository in, such as another GraphDB instance." +
+					" Defaults to the local GraphDB instance. Changing it recreates the repository, except for adding or removing a trailing slash.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "must be an http or https URL"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplaceIf(locationRequiresReplace,
+						"Changing the location recreates the repository.",
+						"Changing the location recreates the repository."),
+				}},
 			"type": schema.StringAttribute{
 				Computed:    true,
-				Description: "Type of Repository"},
+				Description: "Type of Repository",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				}},
+			"state": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "Whether the repository is running or inactive. Inactive repositories free their memory, and GraphDB starts them again when they are used." +
+					" Defaults to leaving the repository as GraphDB manages it.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(repositoryStateRunning, repositoryStateInactive),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				}},
+			"restart_triggers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary values which restart the repository when they change, e.g. the version of a plugin it uses. Inactive repositories are not restarted.",
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+				Description: "Prevent the repository and its data from being deleted, including when a change requires replacing it." +
+					" It must be disabled, and applied, before the repository can be destroyed. Defaults to false.",
+			},
+			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+				Create:            true,
+				Update:            true,
+				CreateDescription: "How long to wait for the repository to be running after creating it. A repository which is still starting is kept, with a warning. Defaults to 10m.",
+				UpdateDescription: "How long to wait for the repository to be running after updating or restarting it. A repository which is still starting is kept, with a warning. Defaults to 10m.",
+			}),
 		},
 	}
 }
 
+func (r *RepositoryResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(path.MatchRoot("config"), path.MatchRoot("config_json"), path.MatchRoot("settings")),
+	}
+}
+
+// ValidateConfig parses the Turtle configuration, so that syntax errors and a repository ID which does not match the
+// name are reported before anything is created.
+func (r *RepositoryResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config RepositoryResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !config.ConfigJSON.IsNull() && !config.ConfigJSON.IsUnknown() {
+		validateConfigJSON(config, &resp.Diagnostics)
+	}
+	if config.Config.IsNull() || config.Config.IsUnknown() {
+		return
+	}
+
+	repo, err := graphdb.ParseRepositoryConfig(config.Config.ValueString())
+	var syntaxErr *turtle.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		resp.Diagnostics.AddAttributeError(path.Root("config"), "Invalid Turtle syntax in config",
+			fmt.Sprintf("The repository configuration could not be parsed, at line %d, column %d: %s", syntaxErr.Line, syntaxErr.Column, syntaxErr.Msg))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("config"), "Invalid repository config", fmt.Sprintf("%s.", err))
+		return
+	}
+	if repo.SesameType == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("config"), "Invalid repository config",
+			"The repository configuration must set rep:repositoryType on its rep:repositoryImpl, e.g. graphdb:SailRepository.")
+	}
+	tflog.Debug(ctx, "Parsed repository config", map[string]any{"id": repo.ID, "label": repo.Title, "type": repo.SesameType})
+
+	if !config.Name.IsUnknown() && !config.Name.IsNull() && repo.ID != config.Name.ValueString() {
+		resp.Diagnostics.AddAttributeError(path.Root("config"), "Repository ID does not match name",
+			fmt.Sprintf("The repository configuration sets rep:repositoryID to %q on line %d, but the name is %q. They must be the same.",
+				repo.ID, repositoryIDLine(config.Config.ValueString()), config.Name.ValueString()))
+	}
+}
+
+// validateConfigJSON checks that the JSON configuration describes a repository with the same ID as the name.
+func validateConfigJSON(config RepositoryResourceModel, diags *diag.Diagnostics) {
+	repo, err := graphdb.ParseRepositoryJSON(config.ConfigJSON.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("config_json"), "Invalid repository config_json", fmt.Sprintf("%s.", err))
+		return
+	}
+	if !config.Name.IsUnknown() && !config.Name.IsNull() && repo.ID != config.Name.ValueString() {
+		diags.AddAttributeError(path.Root("config_json"), "Repository ID does not match name",
+			fmt.Sprintf("The repository configuration sets id to %q, but the name is %q. They must be the same.", repo.ID, config.Name.ValueString()))
+	}
+}
+
+func (r *RepositoryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Only data loss needs checking when the repository is being destroyed
+	if req.Plan.Raw.IsNull() {
+		r.warnDataLoss(ctx, req.State, "destroyed", &resp.Diagnostics)
+		return
+	}
+	if !req.State.Raw.IsNull() {
+		// Check the replacement required by this method too, before returning early below
+		defer func() {
+			if len(resp.RequiresReplace) > 0 || replacementPlanned(ctx, req) {
+				r.warnDataLoss(ctx, req.State, "replaced", &resp.Diagnostics)
+			}
+		}()
+	}
+
+	if !req.State.Raw.IsNull() {
+		var planned, current types.Object
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("settings"), &planned)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("settings"), &current)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// Settings only require replacement when they were already managed, the live configuration is checked on update otherwise
+		if !current.IsNull() {
+			changed := immutableChanges(graphdb.Repository{Params: settingsParams(current, repositorySettingParams)}, graphdb.Repository{Params: settingsParams(planned, repositorySettingParams)})
+			for attribute, param := range repositorySettingParams {
+				for _, name := range changed {
+					if name == param {
+						resp.RequiresReplace.Append(path.Root("settings").AtName(attribute))
+					}
+				}
+			}
+		}
+	}
+
+	for attribute, parse := range map[string]func(string) (graphdb.Repository, error){
+		"config":      graphdb.ParseRepositoryConfig,
+		"config_json": graphdb.ParseRepositoryJSON,
+	} {
+		var config types.String
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root(attribute), &config)...)
+		if resp.Diagnostics.HasError() || config.IsNull() || config.IsUnknown() {
+			continue
+		}
+
+		// Invalid configurations are reported by ValidateConfig
+		repo, err := parse(config.ValueString())
+		if err != nil {
+			continue
+		}
+		if feature, ok := repositoryTypeFeatures[repo.SesameType]; ok {
+			requireFeature(r.server, feature, path.Root(attribute), &resp.Diagnostics)
+		}
+
+		// The description defaults to the label, so follow changes to the label unless the description is configured
+		var description types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("description"), &description)...)
+		if !req.State.Raw.IsNull() && description.IsNull() && repo.Title != "" {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("description"), repo.Title)...)
+		}
+	}
+}
+
 func (r *RepositoryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan RepositoryResourceModel
 
@@ -94,29 +339,88 @@ func (r *RepositoryResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	if plan.Config.ValueString() == "" {
+	createTimeout, diags := plan.Timeouts.Create(ctx, defaultRepositoryCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	config := plan.Config.ValueString()
+	if !plan.Settings.IsNull() {
+		config = graphdb.FormatRepositoryConfig(plannedRepository(plan))
+	}
+	var planned *graphdb.Repository
+	if plan.ConfigJSON.ValueString() != "" {
+		repo, err := graphdb.ParseRepositoryJSON(plan.ConfigJSON.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("config_json"), "Invalid Config", err.Error())
+			return
+		}
+		planned = &repo
+	} else if config == "" {
 		resp.Diagnostics.AddAttributeError(path.Root("config"), "Empty Config", "Config cannot be empty on creation.")
 		return
 	}
-	reader := strings.NewReader(plan.Config.ValueString())
 
-	var err = r.client.CreateRepository(ctx, reader)
+	location := plan.Location.ValueString()
+	repos := r.client.InLocation(canonicalLocation(location))
+	unlock, err := repos.LockRepository(ctx, plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to create Repository", fmt.Sprintf("Interrupted while waiting for other operations on the repository. Error: %s", err))
+		return
+	}
+	defer unlock()
+
+	if planned != nil {
+		err = repos.CreateRepositoryJSON(ctx, *planned)
+	} else {
+		err = repos.CreateRepository(ctx, strings.NewReader(config))
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create Repository", fmt.Sprintf("Failed to create repository. Unexpected error %s", err.Error()))
 		return
 	}
-	// Read the repository back out
-	// TODO: This is unsafe because there could be a mismatch between the config file and the repo name.
-	repo, err := r.client.GetRepository(ctx, plan.Name.ValueString())
+	// Read the repository back out, ValidateConfig ensures that the ID in the config file matches the name
+	repo, err := repos.GetRepository(ctx, plan.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to create Repository", fmt.Sprintf("Failed to retrieve repository after creation. Unexpected error %s", err.Error()))
 		return
 	}
 
-	plan.ID = types.StringValue(repo.ID)
+	// The title comes from the configuration file, so it has to be changed afterwards if the description differs
+	if !plan.Description.IsUnknown() && !plan.Description.IsNull() && plan.Description.ValueString() != repo.Title {
+		repo.Title = plan.Description.ValueString()
+		if err := repos.UpdateRepository(ctx, repo.ID, repo); err != nil {
+			resp.Diagnostics.AddError("Failed to create Repository", fmt.Sprintf("Failed to set repository description. Unexpected error %s", err.Error()))
+			return
+		}
+	}
+
+	if err := applyRepositoryState(ctx, repos, repo.ID, plan.State.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to create Repository", fmt.Sprintf("Failed to change repository state. Unexpected error %s", err.Error()))
+		return
+	}
+	// GraphDB may still be initializing the repository, which fails the resources using it until it is running
+	if plan.State.ValueString() != repositoryStateInactive {
+		if err := waitForRepository(ctx, repos, repo.ID, true); err != nil {
+			// The repository may just be slow to start, so track it as created rather than have Terraform replace it
+			resp.Diagnostics.AddWarning("Repository did not start", fmt.Sprintf("Repository was created, but did not start. %s", err))
+			ctx, cancel = detachedTimeout(ctx)
+			defer cancel()
+		}
+	}
+	if err := readRepositoryState(ctx, repos, repo.ID, &plan.State); err != nil {
+		resp.Diagnostics.AddError("Failed to create Repository", fmt.Sprintf("Failed to retrieve repository state. Unexpected error %s", err.Error()))
+		return
+	}
+
+	plan.ID = types.StringValue(repositoryID(location, repo.ID))
 	plan.Description = types.StringValue(repo.Title)
 	plan.Type = types.StringValue(repo.Type)
-	plan.Location = types.StringValue(repo.Location)
+	plan.Location = types.StringValue(location)
+	plan.Settings = refreshSettings(ctx, plan.Settings, repositorySettingParams, repo.Params, &resp.Diagnostics)
 
 	diags = resp.State.Set(ctx, plan)
 	resp.Diagnostics.Append(diags...)
@@ -136,11 +440,23 @@ func (r *RepositoryResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 	repoName := state.ID.ValueString()
 	tflog.Debug(ctx, "Fetching repository", map[string]any{"id": repoName})
-	err := r.doRead(ctx, repoName, &state)
+	location, name := parseRepositoryID(repoName)
+	err := r.doRead(ctx, location, name, &state, &resp.Diagnostics)
+	if graphdb.IsNotFound(err) {
+		tflog.Warn(ctx, "Repository no longer exists, removing from state", map[string]any{"id": repoName})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to read repository", fmt.Sprintf("Unable to read repository. Unexpected error: %a", err))
+		resp.Diagnostics.AddError("Failed to read repository", fmt.Sprintf("Unable to read repository. Unexpected error: %s", err))
 		return
 	}
+	if state.Config.ValueString() != "" {
+		r.detectConfigDrift(ctx, location, name, &state, &resp.Diagnostics)
+	}
+	if state.ConfigJSON.ValueString() != "" {
+		r.detectConfigJSONDrift(ctx, location, name, &state, &resp.Diagnostics)
+	}
 
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -157,9 +473,27 @@ func (r *RepositoryResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 	repoId := state.ID.ValueString()
+	if state.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError("Repository is protected from deletion",
+			fmt.Sprintf("Repository %s has deletion_protection enabled, so it cannot be destroyed or replaced. Set deletion_protection to false and apply the change first.", repoId))
+		return
+	}
 	tflog.Debug(ctx, "Deleting repository", map[string]any{"id": repoId})
+	location, name := parseRepositoryID(repoId)
+	repos := r.client.InLocation(location)
+
+	unlock, err := repos.LockRepository(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Error deleting repository", fmt.Sprintf("Interrupted while waiting for other operations on the repository. Error: %s", err))
+		return
+	}
+	defer unlock()
 
-	err := r.client.DeleteRepository(ctx, repoId)
+	err = repos.DeleteRepository(ctx, name)
+	if graphdb.IsNotFound(err) {
+		tflog.Debug(ctx, "Repository already deleted", map[string]any{"id": repoId})
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Error deleting repository", fmt.Sprintf("Could not delete repository. Unexpected error: %s", err.Error()))
 		return
@@ -167,23 +501,461 @@ func (r *RepositoryResource) Delete(ctx context.Context, req resource.DeleteRequ
 }
 
 func (r *RepositoryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// TODO: Implement
+	var plan, state RepositoryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, diags := plan.Timeouts.Update(ctx, defaultRepositoryUpdateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, updateTimeout)
+	defer cancel()
+
+	repoId := state.ID.ValueString()
+	tflog.Debug(ctx, "Updating repository", map[string]any{"id": repoId})
+	location, name := parseRepositoryID(repoId)
+	repos := r.client.InLocation(location)
+
+	unlock, err := repos.LockRepository(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Interrupted while waiting for other operations on the repository. Error: %s", err))
+		return
+	}
+	defer unlock()
+
+	// GraphDB replaces the whole configuration, so start from the current one and apply the planned changes to it
+	repo, err := repos.GetRepository(ctx, name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Failed to retrieve repository before update. Unexpected error %s", err.Error()))
+		return
+	}
+
+	var planned graphdb.Repository
+	configPath := path.Root("config")
+	switch {
+	case !plan.Settings.IsNull():
+		planned = plannedRepository(plan)
+		configPath = path.Root("settings")
+	case plan.ConfigJSON.ValueString() != "":
+		configPath = path.Root("config_json")
+		planned, err = graphdb.ParseRepositoryJSON(plan.ConfigJSON.ValueString())
+	default:
+		planned, err = graphdb.ParseRepositoryConfig(plan.Config.ValueString())
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(configPath, "Invalid Config", err.Error())
+		return
+	}
+	// The configuration in state may be missing, e.g. after an import, so check against the live configuration too
+	if changed := immutableChanges(repo, planned); len(changed) > 0 {
+		resp.Diagnostics.AddAttributeError(configPath, "Failed to update Repository",
+			fmt.Sprintf("Cannot change %s of an existing repository in place. Recreate the repository instead, e.g. with -replace.", strings.Join(changed, ", ")))
+		return
+	}
+	repo.Params = updatedParams(repo.Params, planned.Params)
+	if planned.Title != "" {
+		repo.Title = planned.Title
+	}
+	if !plan.Description.IsUnknown() && !plan.Description.IsNull() {
+		repo.Title = plan.Description.ValueString()
+	}
+
+	// Changing only the state or the restart triggers does not edit the repository
+	if !plan.Config.Equal(state.Config) || !plan.ConfigJSON.Equal(state.ConfigJSON) || !plan.Settings.Equal(state.Settings) || !plan.Description.IsUnknown() && !plan.Description.Equal(state.Description) {
+		if err := repos.UpdateRepository(ctx, name, repo); err != nil {
+			resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Failed to update repository. Unexpected error %s", err.Error()))
+			return
+		}
+	}
+
+	desired := plan.State.ValueString()
+	if !plan.RestartTriggers.Equal(state.RestartTriggers) && desired != repositoryStateInactive {
+		tflog.Info(ctx, "Restart triggers changed, restarting repository", map[string]any{"id": repoId})
+		err = repos.RestartRepository(ctx, name)
+	} else {
+		err = applyRepositoryState(ctx, repos, name, desired)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Failed to change repository state. Unexpected error %s", err.Error()))
+		return
+	}
+	if desired != repositoryStateInactive {
+		if err := waitForRepository(ctx, repos, name, desired == repositoryStateRunning); err != nil {
+			resp.Diagnostics.AddWarning("Repository did not start", fmt.Sprintf("Repository was updated, but did not start. %s", err))
+			ctx, cancel = detachedTimeout(ctx)
+			defer cancel()
+		}
+	}
+	if err := r.doRead(ctx, location, name, &plan, &resp.Diagnostics); err != nil {
+		resp.Diagnostics.AddError("Failed to update Repository", fmt.Sprintf("Failed to retrieve repository after update. Unexpected error %s", err.Error()))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
+// ImportState imports a repository by its ID, which is its name for a repository in the local GraphDB instance, or
+// its location and name separated by a slash, e.g. http://remote:7200/myrepo, for one in a remote location.
 func (r *RepositoryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
-func (r *RepositoryResource) doRead(ctx context.Context, id string, data *RepositoryResourceModel) error {
-	repo, err := r.client.GetRepository(ctx, id)
+func (r *RepositoryResource) doRead(ctx context.Context, location string, name string, data *RepositoryResourceModel, diags *diag.Diagnostics) error {
+	repos := r.client.InLocation(location)
+	repo, err := repos.GetRepository(ctx, name)
 	if err != nil {
 		return err
 	}
+	if err := readRepositoryState(ctx, repos, name, &data.State); err != nil {
+		return err
+	}
 
-	data.ID = types.StringValue(repo.ID)
+	data.ID = types.StringValue(repositoryID(location, repo.ID))
 	data.Name = types.StringValue(repo.ID)
 	data.Description = types.StringValue(repo.Title)
 	data.Type = types.StringValue(repo.Type)
-	data.Location = types.StringValue(repo.Location)
+	data.Location = refreshLocation(data.Location, location)
+	data.Settings = refreshSettings(ctx, data.Settings, repositorySettingParams, repo.Params, diags)
+	if data.DeletionProtection.IsNull() {
+		data.DeletionProtection = types.BoolValue(false)
+	}
+	return nil
+}
+
+// warnDataLoss warns that the repository in state is about to be destroyed or replaced if it holds any statements,
+// so that the plan shows how much data would be lost. The check is best effort, and skipped if the size is unavailable.
+func (r *RepositoryResource) warnDataLoss(ctx context.Context, state tfsdk.State, action string, diags *diag.Diagnostics) {
+	var data RepositoryResourceModel
+	if d := state.Get(ctx, &data); d.HasError() || r.client == nil {
+		return
+	}
+	location, name := parseRepositoryID(data.ID.ValueString())
+	size, err := r.client.InLocation(location).GetRepositorySize(ctx, name)
+	if err != nil {
+		tflog.Debug(ctx, "Unable to get repository size", map[string]any{"id": data.ID.ValueString(), "error": err.Error()})
+		return
+	}
+	if size.Total == 0 && size.Explicit == 0 {
+		return
+	}
+
+	detail := fmt.Sprintf("Repository %s holds %d statements (%d explicit, %d inferred), which will be deleted when it is %s.",
+		data.ID.ValueString(), size.Total, size.Explicit, size.Inferred, action)
+	if data.DeletionProtection.ValueBool() {
+		detail += " Its deletion_protection will make the apply fail."
+	}
+	diags.AddWarning("Repository holding data will be "+action, detail)
+}
+
+// detectConfigDrift compares the live configuration of the repository with the one in state. If they differ in the
+// settings the configuration sets, the live configuration replaces it in state, so that the plan proposes to restore it.
+func (r *RepositoryResource) detectConfigDrift(ctx context.Context, location string, id string, data *RepositoryResourceModel, diags *diag.Diagnostics) {
+	live, err := r.client.InLocation(location).GetRepositoryConfig(ctx, id)
+	if graphdb.IsNotFound(err) {
+		tflog.Debug(ctx, "GraphDB cannot download repository configurations, skipping drift detection", map[string]any{"id": id})
+		return
+	}
+	if err != nil {
+		diags.AddWarning("Unable to detect repository config drift", fmt.Sprintf("Failed to download the live repository configuration. Error: %s", err))
+		return
+	}
+
+	drift, err := graphdb.RepositoryConfigDrift(data.Config.ValueString(), live)
+	if err != nil {
+		diags.AddWarning("Unable to detect repository config drift", fmt.Sprintf("Failed to compare the live repository configuration. Error: %s", err))
+		return
+	}
+	if len(drift) > 0 {
+		tflog.Info(ctx, "Repository configuration changed outside of Terraform", map[string]any{"id": id, "settings": drift})
+		data.Config = types.StringValue(live)
+	}
+}
+
+// detectConfigJSONDrift compares the live repository with the JSON configuration in state. If they differ in the
+// settings the configuration sets, their live values replace the configured ones in state, so that the plan proposes
+// to restore them.
+func (r *RepositoryResource) detectConfigJSONDrift(ctx context.Context, location string, id string, data *RepositoryResourceModel, diags *diag.Diagnostics) {
+	live, err := r.client.InLocation(location).GetRepository(ctx, id)
+	if err != nil {
+		diags.AddWarning("Unable to detect repository config drift", fmt.Sprintf("Failed to retrieve the live repository. Error: %s", err))
+		return
+	}
+
+	refreshed, drift, err := graphdb.RefreshRepositoryJSON(data.ConfigJSON.ValueString(), live)
+	if err != nil {
+		diags.AddWarning("Unable to detect repository config drift", fmt.Sprintf("Failed to compare the live repository configuration. Error: %s", err))
+		return
+	}
+	if len(drift) > 0 {
+		tflog.Info(ctx, "Repository configuration changed outside of Terraform", map[string]any{"id": id, "settings": drift})
+		data.ConfigJSON = types.StringValue(refreshed)
+	}
+}
+
+// Values of the state attribute.
+const (
+	repositoryStateRunning  = "running"
+	repositoryStateInactive = "inactive"
+)
+
+// repositoryState returns the value of the state attribute for a state reported by GraphDB. Repositories which are
+// starting are considered running, and those which are stopping inactive, as that is where they are heading.
+func repositoryState(state string) string {
+	switch state {
+	case graphdb.RepositoryStateInactive, graphdb.RepositoryStateStopping:
+		return repositoryStateInactive
+	default:
+		return repositoryStateRunning
+	}
+}
+
+// readRepositoryState sets state to the live state of the repository. If GraphDB does not report it, state is left
+// unchanged, or set to null if it is unknown.
+func readRepositoryState(ctx context.Context, repos graphdb.RepositoriesAPI, name string, state *types.String) error {
+	live, err := repos.GetRepositoryState(ctx, name)
+	if err != nil {
+		return err
+	}
+	if live == "" {
+		tflog.Debug(ctx, "GraphDB does not report the repository state", map[string]any{"id": name})
+		if state.IsUnknown() {
+			*state = types.StringNull()
+		}
+		return nil
+	}
+	*state = types.StringValue(repositoryState(live))
 	return nil
 }
+
+// applyRepositoryState starts or stops the repository if its live state differs from desired, if set.
+func applyRepositoryState(ctx context.Context, repos graphdb.RepositoriesAPI, name string, desired string) error {
+	if desired == "" {
+		return nil
+	}
+	live, err := repos.GetRepositoryState(ctx, name)
+	if err != nil || repositoryState(live) == desired {
+		return err
+	}
+	tflog.Info(ctx, "Changing repository state", map[string]any{"id": name, "from": live, "to": desired})
+	if desired == repositoryStateInactive {
+		return repos.ShutdownRepository(ctx, name)
+	}
+	return repos.RestartRepository(ctx, name)
+}
+
+// waitForRepository polls the state of the repository until it is running, or ctx is done. Inactive repositories are
+// started once if start is set, and considered ready otherwise, as GraphDB starts them when they are used. The error
+// on timeout reports the last state observed.
+func waitForRepository(ctx context.Context, repos graphdb.RepositoriesAPI, name string, start bool) error {
+	last := "unknown"
+	started := false
+	for {
+		live, err := repos.GetRepositoryState(ctx, name)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			return fmt.Errorf("Failed to retrieve repository state: %w", err)
+		case err != nil:
+		case live == "" || live == graphdb.RepositoryStateRunning:
+			return nil
+		case live == graphdb.RepositoryStateInactive && !start:
+			return nil
+		case live == graphdb.RepositoryStateInactive && !started:
+			tflog.Info(ctx, "Starting inactive repository", map[string]any{"id": name})
+			if err := repos.RestartRepository(ctx, name); err != nil && ctx.Err() == nil {
+				return fmt.Errorf("Failed to start repository: %w", err)
+			}
+			started = true
+			last = live
+		default:
+			last = live
+		}
+		tflog.Debug(ctx, "Waiting for repository to be running", map[string]any{"id": name, "state": last})
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Timed out waiting for repository %s to be running, last observed state: %s", name, last)
+		case <-time.After(repositoryPollInterval):
+		}
+	}
+}
+
+// detachedTimeout returns a context to read a repository with once waiting for it to start has failed, keeping the
+// values of ctx, such as the logger, but not its deadline, which has usually expired by then.
+func detachedTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), repositoryReadTimeout)
+}
+
+// repositoryID identifies a repository in the given location, an empty location being the local GraphDB instance.
+// The location is canonicalized, so that a repository has a single ID however its location is written.
+func repositoryID(location string, name string) string {
+	if location == "" {
+		return name
+	}
+	return canonicalLocation(location) + "/" + name
+}
+
+// canonicalLocation returns the location without a trailing slash, e.g. http://remote:7200/graphdb for
+// http://remote:7200/graphdb/.
+func canonicalLocation(location string) string {
+	return strings.TrimSuffix(location, "/")
+}
+
+// refreshLocation returns the location read back for a repository, keeping the current value as configured as long as it
+// is the same location.
+func refreshLocation(current types.String, location string) types.String {
+	if current.IsNull() || current.IsUnknown() || canonicalLocation(current.ValueString()) != location {
+		return types.StringValue(location)
+	}
+	return current
+}
+
+// locationRequiresReplace requires the repository to be recreated when it moves to another location, which it does not
+// when only a trailing slash is added or removed.
+func locationRequiresReplace(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = canonicalLocation(req.StateValue.ValueString()) != canonicalLocation(req.PlanValue.ValueString())
+}
+
+// parseRepositoryID returns the location and the name of the repository identified by id.
+func parseRepositoryID(id string) (string, string) {
+	i := strings.LastIndex(id, "/")
+	if i < 0 {
+		return "", id
+	}
+	return id[:i], id[i+1:]
+}
+
+// plannedRepository returns the repository described by the settings attribute.
+func plannedRepository(plan RepositoryResourceModel) graphdb.Repository {
+	repo := graphdb.Repository{
+		ID:     plan.Name.ValueString(),
+		Type:   "graphdb",
+		Params: settingsParams(plan.Settings, repositorySettingParams),
+	}
+	if !plan.Description.IsUnknown() {
+		repo.Title = plan.Description.ValueString()
+	}
+	return repo
+}
+
+// updatedParams returns the parameters to update a repository with, given its live parameters and the planned ones.
+// Parameters which are no longer planned are left out, so that GraphDB resets them to their defaults, except for those
+// which cannot be changed in place. The labels of the live parameters are kept.
+func updatedParams(live map[string]graphdb.RepositoryParam, planned map[string]graphdb.RepositoryParam) map[string]graphdb.RepositoryParam {
+	params := map[string]graphdb.RepositoryParam{}
+	for _, name := range immutableRepositoryParams {
+		if param, ok := live[name]; ok {
+			params[name] = param
+		}
+	}
+	for name, param := range planned {
+		if current, ok := live[name]; ok {
+			param.Label = current.Label
+		}
+		params[name] = param
+	}
+	return params
+}
+
+// replacementPlanned reports whether the plan modifiers of the attributes require replacing the repository, as the
+// framework does not pass their decision on to ModifyPlan.
+func replacementPlanned(ctx context.Context, req resource.ModifyPlanRequest) bool {
+	var plan, state RepositoryResourceModel
+	if req.Plan.Get(ctx, &plan).HasError() || req.State.Get(ctx, &state).HasError() {
+		return false
+	}
+	changed := func(planned, current types.String) bool {
+		return !planned.IsUnknown() && !planned.IsNull() && !planned.Equal(current)
+	}
+	if changed(plan.Name, state.Name) {
+		return true
+	}
+
+	resp := &stringplanmodifier.RequiresReplaceIfFuncResponse{}
+	if changed(plan.Location, state.Location) {
+		locationRequiresReplace(ctx, planmodifier.StringRequest{PlanValue: plan.Location, StateValue: state.Location}, resp)
+	}
+	if !resp.RequiresReplace {
+		configRequiresReplace(ctx, planmodifier.StringRequest{PlanValue: plan.Config, StateValue: state.Config}, resp)
+	}
+	if !resp.RequiresReplace {
+		configJSONRequiresReplace(ctx, planmodifier.StringRequest{PlanValue: plan.ConfigJSON, StateValue: state.ConfigJSON}, resp)
+	}
+	return resp.RequiresReplace
+}
+
+// configRequiresReplace requires the repository to be recreated when the configuration changes its type or a
+// parameter which cannot be changed in place.
+func configRequiresReplace(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	parsedConfigRequiresReplace(ctx, req, resp, graphdb.ParseRepositoryConfig)
+}
+
+// configJSONRequiresReplace is configRequiresReplace for the configuration in JSON form.
+func configJSONRequiresReplace(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	parsedConfigRequiresReplace(ctx, req, resp, graphdb.ParseRepositoryJSON)
+}
+
+func parsedConfigRequiresReplace(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse, parse func(string) (graphdb.Repository, error)) {
+	if req.PlanValue.IsUnknown() || req.PlanValue.ValueString() == "" || req.StateValue.ValueString() == "" {
+		return
+	}
+	current, err := parse(req.StateValue.ValueString())
+	if err != nil {
+		return
+	}
+	planned, err := parse(req.PlanValue.ValueString())
+	if err != nil {
+		return
+	}
+	if changed := immutableChanges(current, planned); len(changed) > 0 {
+		tflog.Debug(ctx, "Repository configuration changes require replacement", map[string]any{"changed": changed})
+		resp.RequiresReplace = true
+	}
+}
+
+// immutableParamNames returns the names of the parameters which cannot be changed in place, as they are written in
+// configuration files.
+func immutableParamNames() []string {
+	names := make([]string, 0, len(immutableRepositoryParams))
+	for _, key := range immutableRepositoryParams {
+		names = append(names, graphdb.ParamLocalName(key))
+	}
+	return names
+}
+
+// immutableChanges returns the settings which cannot be changed in place and have different values in planned than in
+// current. Parameters missing from planned keep their current values on update, so they are not considered.
+func immutableChanges(current graphdb.Repository, planned graphdb.Repository) []string {
+	var changed []string
+	if planned.Type != "" && planned.Type != current.Type {
+		changed = append(changed, "type")
+	}
+	for _, name := range immutableRepositoryParams {
+		param, ok := planned.Params[name]
+		if !ok {
+			continue
+		}
+		if existing, ok := current.Params[name]; !ok || existing.Value != param.Value {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// repositoryIDLine returns the line of the configuration which sets the repository ID, or 0 if it cannot be found.
+func repositoryIDLine(config string) int {
+	g, err := turtle.Parse(config)
+	if err != nil {
+		return 0
+	}
+	for _, t := range g.Triples {
+		if t.Predicate.Value == graphdb.NamespaceRep+"repositoryID" {
+			return t.Line
+		}
+	}
+	return 0
+}
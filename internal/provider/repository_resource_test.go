@@ -4,9 +4,22 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb/graphdbtest"
 )
 
 func TestAccRepositoryResource(t *testing.T) {
@@ -15,13 +28,13 @@ func TestAccRepositoryResource(t *testing.T) {
 		Steps: []resource.TestStep{
 			// Test Create and Read
 			{
-				Config: providerConfig + `
+				Config: providerConfig + fmt.Sprintf(`
 resource "graphdb_repository" "test" {
   name = "TestRepo"
-  config = file("/Users/nickrobison/Downloads/TestRepo-config.ttl")
+  config = file(%q)
   description = ""
 }
-`,
+`, testDataPath(t, "TestRepo-config.ttl")),
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("graphdb_repository.test", "name", "TestRepo"),
 				),
@@ -36,3 +49,988 @@ resource "graphdb_repository" "test" {
 		},
 	})
 }
+
+func TestRepositoryResource(t *testing.T) {
+	server := newTestServer(t)
+	config := testProviderConfig(server) + fmt.Sprintf(`
+resource "graphdb_repository" "test" {
+  name = "TestRepo"
+  config = file(%q)
+  description = ""
+}
+`, testDataPath(t, "TestRepo-config.ttl"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Test Create and Read
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_repository.test", "id", "TestRepo"),
+					resource.TestCheckResourceAttr("graphdb_repository.test", "name", "TestRepo"),
+					resource.TestCheckResourceAttr("graphdb_repository.test", "type", "graphdb"),
+				),
+			},
+			// Test import
+			{
+				ResourceName:            "graphdb_repository.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"config"},
+			},
+			// Test that a repository deleted out-of-band is recreated
+			{
+				PreConfig: func() {
+					server.RemoveRepository("TestRepo")
+				},
+				Config: config,
+				Check: func(_ *terraform.State) error {
+					if _, ok := server.Repository("TestRepo"); !ok {
+						return fmt.Errorf("Repository was not recreated")
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestRepositoryResourceUpdate(t *testing.T) {
+	server := newTestServer(t)
+	config := func(description string, label string, ruleset string, params string) string {
+		return testProviderConfig(server) + fmt.Sprintf(`
+resource "graphdb_repository" "test" {
+  name = "TestRepo"
+  %s
+  config = <<EOT
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#>.
+@prefix rep: <http://www.openrdf.org/config/repository#>.
+@prefix sail: <http://www.openrdf.org/config/sail#>.
+@prefix sr: <http://www.openrdf.org/config/repository/sail#>.
+@prefix graphdb: <http://www.ontotext.com/config/graphdb#>.
+
+[] a rep:Repository ;
+    rep:repositoryID "TestRepo" ;
+    rdfs:label %q ;
+    rep:repositoryImpl [
+        rep:repositoryType "graphdb:SailRepository" ;
+        sr:sailImpl [
+            sail:sailType "graphdb:Sail" ;
+            %s
+            graphdb:ruleset %q
+        ]
+    ].
+EOT
+}
+`, description, label, params, ruleset)
+	}
+	second := config(`description = "Second"`, "Test repository", "rdfsplus-optimized", `graphdb:query-timeout "60" ;`)
+	reformatted := strings.NewReplacer("@prefix graphdb:", "@prefix gdb:", "graphdb:ruleset", "gdb:ruleset", "graphdb:query-timeout", "gdb:query-timeout", "\n        ]", " ]").
+		Replace(second)
+	checkRepository := func(title string, updates int) resource.TestCheckFunc {
+		return func(_ *terraform.State) error {
+			if repo, _ := server.Repository("TestRepo"); repo.Title != title {
+				return fmt.Errorf("Unexpected title. Wanted: %s. Got: %s", title, repo.Title)
+			}
+			if n := server.RepositoryUpdates("TestRepo"); n != updates {
+				return fmt.Errorf("Unexpected number of updates. Wanted: %d. Got: %d", updates, n)
+			}
+			return nil
+		}
+	}
+	expectUpdate := resource.ConfigPlanChecks{
+		PreApply: []plancheck.PlanCheck{
+			plancheck.ExpectResourceAction("graphdb_repository.test", plancheck.ResourceActionUpdate),
+		},
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The description differs from the label, so it is set after creating the repository
+			{
+				Config: config(`description = "First"`, "Test repository", "rdfsplus-optimized", `graphdb:query-timeout "30" ;`),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_repository.test", "description", "First"),
+					checkRepository("First", 1),
+					checkRepositoryParams(server, "TestRepo", map[string]string{"queryTimeout": "30"}),
+				),
+			},
+			// Test that the description and parameters are changed in place
+			{
+				Config:           second,
+				ConfigPlanChecks: expectUpdate,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_repository.test", "description", "Second"),
+					checkRepository("Second", 2),
+					checkRepositoryParams(server, "TestRepo", map[string]string{"ruleset": "rdfsplus-optimized", "queryTimeout": "60"}),
+				),
+			},
+			// Test that out-of-band changes to the configuration are detected and corrected in place,
+			// while the same configuration with different formatting and prefixes is not a change
+			{
+				PreConfig: func() {
+					server.SetRepositoryParam("TestRepo", "queryTimeout", "99")
+				},
+				Config:             second,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config:           second,
+				ConfigPlanChecks: expectUpdate,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkRepository("Second", 3),
+					checkRepositoryParams(server, "TestRepo", map[string]string{"queryTimeout": "60"}),
+				),
+			},
+			{
+				Config:           reformatted,
+				ConfigPlanChecks: expectUpdate,
+			},
+			{
+				Config:   reformatted,
+				PlanOnly: true,
+			},
+			// Test that a parameter removed from the configuration is removed from the repository, so that GraphDB
+			// resets it to its default
+			{
+				Config:           config(`description = "Second"`, "Test repository", "rdfsplus-optimized", ""),
+				ConfigPlanChecks: expectUpdate,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkRepository("Second", 5),
+					func(_ *terraform.State) error {
+						if repo, _ := server.Repository("TestRepo"); len(repo.Params) != 1 || repo.Params["ruleset"].Value != "rdfsplus-optimized" {
+							return fmt.Errorf("Expected only the ruleset to be left. Got: %+v", repo.Params)
+						}
+						return nil
+					},
+				),
+			},
+			// Test that without a description, it follows changes to the label
+			{
+				Config:           config("", "Renamed repository", "rdfsplus-optimized", ""),
+				ConfigPlanChecks: expectUpdate,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_repository.test", "description", "Renamed repository"),
+					checkRepository("Renamed repository", 6),
+				),
+			},
+			// Test that changing the ruleset recreates the repository
+			{
+				Config: config("", "Renamed repository", "owl-horst-optimized", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_repository.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					checkRepository("Renamed repository", 0),
+					checkRepositoryParams(server, "TestRepo", map[string]string{"ruleset": "owl-horst-optimized"}),
+				),
+			},
+		},
+	})
+}
+
+func TestRepositoryResourceSettings(t *testing.T) {
+	server := newTestServer(t)
+	config := func(ruleset string, timeout int, extra string) string {
+		return testProviderConfig(server) + fmt.Sprintf(`
+resource "graphdb_repository" "test" {
+  name = "TestRepo"
+  settings = {
+    ruleset = %q
+    query_timeout = %d
+    %s
+  }
+}
+`, ruleset, timeout, extra)
+	}
+	optional := `
+    read_only = false
+    fts_indexes = ["default", "iri"]`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Test that config and settings are mutually exclusive
+			{
+				Config: testProviderConfig(server) + fmt.Sprintf(`
+resource "graphdb_repository" "test" {
+  name = "TestRepo"
+  config = file(%q)
+  settings = {
+    ruleset = "owl-horst-optimized"
+  }
+}
+`, testDataPath(t, "TestRepo-config.ttl")),
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+			// Test Create and Read
+			{
+				Config: config("rdfsplus-optimized", 0, optional),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_repository.test", "type", "graphdb"),
+					resource.TestCheckResourceAttr("graphdb_repository.test", "settings.fts_indexes.#", "2"),
+					checkRepositoryParams(server, "TestRepo", map[string]string{"ruleset": "rdfsplus-optimized", "queryTimeout": "0", "readOnly": "false", "ftsIndexes": "default, iri"}),
+				),
+			},
+			// Test that settings are changed in place, without touching the description
+			{
+				Config: config("rdfsplus-optimized", 60, optional),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_repository.test", plancheck.ResourceActionUpdate),
+						expectKnownValue{resourceAddress: "graphdb_repository.test", attribute: "description"},
+					},
+				},
+				Check: checkRepositoryParams(server, "TestRepo", map[string]string{"queryTimeout": "60"}),
+			},
+			// Test that removed settings are reset to the GraphDB defaults, while those affecting storage are kept
+			{
+				Config: testProviderConfig(server) + `
+resource "graphdb_repository" "test" {
+  name = "TestRepo"
+  settings = {
+    query_timeout = 60
+  }
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_repository.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: func(_ *terraform.State) error {
+					repo, _ := server.Repository("TestRepo")
+					if len(repo.Params) != 2 || repo.Params["ruleset"].Value != "rdfsplus-optimized" || repo.Params["queryTimeout"].Value != "60" {
+						return fmt.Errorf("Expected only the ruleset and query timeout to be left. Got: %+v", repo.Params)
+					}
+					if n := server.RepositoryUpdates("TestRepo"); n != 2 {
+						return fmt.Errorf("Expected the repository to be updated twice. Got %d updates", n)
+					}
+					return nil
+				},
+			},
+			// Test that changing the ruleset recreates the repository
+			{
+				Config: config("owl-horst-optimized", 60, ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_repository.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: checkRepositoryParams(server, "TestRepo", map[string]string{"ruleset": "owl-horst-optimized", "queryTimeout": "60"}),
+			},
+		},
+	})
+}
+
+// TestRepositoryResourceSettingKeys checks that every setting is sent to GraphDB with the key GraphDB uses for it,
+// which the fake server cannot check since it only ignores keys which are not in camelCase.
+func TestRepositoryResourceSettingKeys(t *testing.T) {
+	server := newTestServer(t)
+	want := map[string]string{
+		"ruleset":                 "owl-horst-optimized",
+		"baseURL":                 "http://example.org/owlim#",
+		"entityIndexSize":         "10000000",
+		"entityIdSize":            "32",
+		"disableSameAs":           "true",
+		"checkForInconsistencies": "false",
+		"queryTimeout":            "30",
+		"queryLimitResults":       "100",
+		"readOnly":                "false",
+		"enableContextIndex":      "true",
+		"enableLiteralIndex":      "true",
+		"enableFtsIndex":          "true",
+		"ftsIndexes":              "default, iri",
+		"ftsStringLiteralsIndex":  "default",
+		"ftsIrisIndex":            "none",
+	}
+	if len(want) != len(repositorySettingParams) {
+		t.Fatalf("Expected a key for each of the %d settings", len(repositorySettingParams))
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig(server) + `
+resource "graphdb_repository" "test" {
+  name = "TestRepo"
+  settings = {
+    ruleset = "owl-horst-optimized"
+    base_url = "http://example.org/owlim#"
+    entity_index_size = 10000000
+    entity_id_size = 32
+    disable_same_as = true
+    check_for_inconsistencies = false
+    query_timeout = 30
+    query_limit_results = 100
+    read_only = false
+    enable_context_index = true
+    enable_literal_index = true
+    enable_fts_index = true
+    fts_indexes = ["default", "iri"]
+    fts_string_literals_index = "default"
+    fts_iris_index = "none"
+  }
+}
+`,
+				Check: func(_ *terraform.State) error {
+					repo, _ := server.Repository("TestRepo")
+					if len(repo.Params) != len(want) {
+						return fmt.Errorf("Unexpected parameters. Wanted: %v. Got: %+v", want, repo.Params)
+					}
+					return checkRepositoryParams(server, "TestRepo", want)(nil)
+				},
+			},
+		},
+	})
+}
+
+// checkRepositoryParams checks the values of the parameters of a repository on the fake server.
+func checkRepositoryParams(server *graphdbtest.Server, id string, want map[string]string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		repo, ok := server.Repository(id)
+		if !ok {
+			return fmt.Errorf("Repository %s does not exist", id)
+		}
+		for name, value := range want {
+			if param, ok := repo.Params[name]; !ok || param.Value != value {
+				return fmt.Errorf("Unexpected value for %s. Wanted: %s. Got: %+v", name, value, repo.Params)
+			}
+		}
+		return nil
+	}
+}
+
+// expectKnownValue is a plan check asserting that a top-level attribute of a resource is known before apply.
+type expectKnownValue struct {
+	resourceAddress string
+	attribute       string
+}
+
+func (e expectKnownValue) CheckPlan(ctx context.Context, req plancheck.CheckPlanRequest, resp *plancheck.CheckPlanResponse) {
+	for _, rc := range req.Plan.ResourceChanges {
+		if rc.Address != e.resourceAddress {
+			continue
+		}
+		if unknown, ok := rc.Change.AfterUnknown.(map[string]any); ok && unknown[e.attribute] == true {
+			resp.Error = fmt.Errorf("Expected %s of %s to be known, but it is planned as (known after apply)", e.attribute, e.resourceAddress)
+		}
+		return
+	}
+	resp.Error = fmt.Errorf("%s - Resource not found in plan ResourceChanges", e.resourceAddress)
+}
+
+func TestRepositoryResourceConfigJSON(t *testing.T) {
+	server := newTestServer(t)
+	config := func(ruleset string, entityIDSize string, timeout string) string {
+		queryTimeout := ""
+		if timeout != "" {
+			queryTimeout = fmt.Sprintf(`queryTimeout = { name = "queryTimeout", value = %q }`, timeout)
+		}
+		return testProviderConfig(server) + fmt.Sprintf(`
+resource "graphdb_repository" "test" {
+  name = "TestRepo"
+  config_json = jsonencode({
+    id    = "TestRepo"
+    title = "Test repository"
+    type  = "graphdb"
+    params = {
+      ruleset      = { name = "ruleset", value = %q }
+      entityIdSize = { name = "entityIdSize", value = %q }
+      %s
+    }
+  })
+}
+`, ruleset, entityIDSize, queryTimeout)
+	}
+	checkParam := func(name string, want string) resource.TestCheckFunc {
+		return func(_ *terraform.State) error {
+			repo, _ := server.Repository("TestRepo")
+			if got := repo.Params[name].Value; got != want {
+				return fmt.Errorf("Unexpected value for %s. Wanted: %s. Got: %s", name, want, got)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Test Create and Read
+			{
+				Config: config("rdfsplus-optimized", "32", "0"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_repository.test", "description", "Test repository"),
+					resource.TestCheckResourceAttr("graphdb_repository.test", "type", "graphdb"),
+					checkParam("ruleset", "rdfsplus-optimized"),
+					checkParam("queryTimeout", "0"),
+				),
+			},
+			// Test that the live configuration round-trips without changes
+			{
+				Config:   config("rdfsplus-optimized", "32", "0"),
+				PlanOnly: true,
+			},
+			// Test that mutable parameters are changed in place
+			{
+				Config: config("rdfsplus-optimized", "32", "30"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_repository.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: checkParam("queryTimeout", "30"),
+			},
+			// Test that out-of-band changes are detected and corrected in place
+			{
+				PreConfig: func() {
+					server.SetRepositoryParam("TestRepo", "queryTimeout", "99")
+				},
+				Config: config("rdfsplus-optimized", "32", "30"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_repository.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: checkParam("queryTimeout", "30"),
+			},
+			// Test that a removed parameter is reset to the GraphDB default
+			{
+				Config: config("rdfsplus-optimized", "32", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_repository.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: func(_ *terraform.State) error {
+					repo, _ := server.Repository("TestRepo")
+					if _, ok := repo.Params["queryTimeout"]; ok {
+						return fmt.Errorf("Expected the query timeout to be removed. Got: %+v", repo.Params)
+					}
+					if n := server.RepositoryUpdates("TestRepo"); n != 3 {
+						return fmt.Errorf("Expected the repository to be updated 3 times. Got %d updates", n)
+					}
+					return nil
+				},
+			},
+			// Test that changing a parameter affecting storage recreates the repository
+			{
+				Config: config("rdfsplus-optimized", "40", "30"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_repository.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: checkParam("entityIdSize", "40"),
+			},
+			// Test that changing the ruleset recreates the repository
+			{
+				Config: config("owl-horst-optimized", "40", "30"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_repository.test", plancheck.ResourceActionDestroyBeforeCreate),
+					},
+				},
+				Check: checkParam("ruleset", "owl-horst-optimized"),
+			},
+		},
+	})
+}
+
+func TestRepositoryResourceInvalidConfigJSON(t *testing.T) {
+	server := newTestServer(t)
+	config := func(configJSON string) string {
+		return testProviderConfig(server) + fmt.Sprintf(`
+resource "graphdb_repository" "test" {
+  name        = "TestRepo"
+  config_json = %s
+}
+`, configJSON)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(`jsonencode({ id = "OtherRepo" })`),
+				ExpectError: regexp.MustCompile(`Repository ID does not match name`),
+			},
+			{
+				Config:      config(`"{ not json"`),
+				ExpectError: regexp.MustCompile(`Invalid repository config_json`),
+			},
+			{
+				Config: testProviderConfig(server) + `
+resource "graphdb_repository" "test" {
+  name        = "TestRepo"
+  config_json = jsonencode({ id = "TestRepo" })
+  settings    = {}
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+		},
+	})
+}
+
+func TestRepositoryResourceLocation(t *testing.T) {
+	for _, remote := range []string{"http://remote:7200", "http://remote:7200/graphdb", "http://remote:7200/graphdb/"} {
+		t.Run(remote, func(t *testing.T) {
+			server := newTestServer(t, graphdbtest.WithLocation(remote))
+			config := func(location string) string {
+				return testProviderConfig(server) + fmt.Sprintf(`
+resource "graphdb_repository" "test" {
+  name = "TestRepo"
+  location = %q
+  config = file(%q)
+}
+`, location, testDataPath(t, "TestRepo-config.ttl"))
+			}
+			// The ID is the same however the location is written
+			id := strings.TrimSuffix(remote, "/") + "/TestRepo"
+			other := remote + "/"
+			if strings.HasSuffix(remote, "/") {
+				other = strings.TrimSuffix(remote, "/")
+			}
+
+			resource.UnitTest(t, resource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					// Test Create and Read
+					{
+						Config: config(remote),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("graphdb_repository.test", "id", id),
+							resource.TestCheckResourceAttr("graphdb_repository.test", "name", "TestRepo"),
+							resource.TestCheckResourceAttr("graphdb_repository.test", "location", remote),
+							func(_ *terraform.State) error {
+								if _, ok := server.Repository(id); !ok {
+									return fmt.Errorf("Repository was not created in the remote location")
+								}
+								if _, ok := server.Repository("TestRepo"); ok {
+									return fmt.Errorf("Repository was created in the local location")
+								}
+								return nil
+							},
+						),
+					},
+					// Test that the location read back matches the configuration
+					{
+						Config:   config(remote),
+						PlanOnly: true,
+					},
+					// Test that adding or removing a trailing slash does not move the repository
+					{
+						Config: config(other),
+						ConfigPlanChecks: resource.ConfigPlanChecks{
+							PreApply: []plancheck.PlanCheck{
+								plancheck.ExpectResourceAction("graphdb_repository.test", plancheck.ResourceActionUpdate),
+							},
+						},
+						Check: resource.TestCheckResourceAttr("graphdb_repository.test", "id", id),
+					},
+					// Test import by location and name
+					{
+						ResourceName:            "graphdb_repository.test",
+						ImportState:             true,
+						ImportStateId:           id,
+						ImportStateVerify:       true,
+						ImportStateVerifyIgnore: []string{"config", "location"},
+						ImportStateCheck: func(states []*terraform.InstanceState) error {
+							if location := states[0].Attributes["location"]; location != strings.TrimSuffix(remote, "/") {
+								return fmt.Errorf("Unexpected location: %s", location)
+							}
+							return nil
+						},
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestRepositoryID(t *testing.T) {
+	for location, canonical := range map[string]string{
+		"":                            "",
+		"http://remote:7200":          "http://remote:7200",
+		"http://remote:7200/graphdb":  "http://remote:7200/graphdb",
+		"http://remote:7200/graphdb/": "http://remote:7200/graphdb",
+		"https://user@remote/a/b/":    "https://user@remote/a/b",
+	} {
+		id := repositoryID(location, "TestRepo")
+		if gotLocation, gotName := parseRepositoryID(id); gotLocation != canonical || gotName != "TestRepo" {
+			t.Fatalf("Unexpected location and name for %s. Wanted: %q, TestRepo. Got: %q, %q", id, canonical, gotLocation, gotName)
+		}
+	}
+}
+
+func TestRepositoryResourceState(t *testing.T) {
+	server := newTestServer(t)
+	config := func(state string, plugin string) string {
+		return testProviderConfig(server) + fmt.Sprintf(`
+resource "graphdb_repository" "test" {
+  name   = "TestRepo"
+  config = file(%q)
+  state  = %q
+  restart_triggers = {
+    plugin = %q
+  }
+}
+`, testDataPath(t, "TestRepo-config.ttl"), state, plugin)
+	}
+	checkServer := func(state string, restarts int) resource.TestCheckFunc {
+		return func(_ *terraform.State) error {
+			if got := server.RepositoryState("TestRepo"); got != state {
+				return fmt.Errorf("Unexpected repository state. Wanted: %s. Got: %s", state, got)
+			}
+			if got := server.RepositoryRestarts("TestRepo"); got != restarts {
+				return fmt.Errorf("Unexpected number of restarts. Wanted: %d. Got: %d", restarts, got)
+			}
+			if updates := server.RepositoryUpdates("TestRepo"); updates != 0 {
+				return fmt.Errorf("Expected the repository not to be edited. Got %d updates", updates)
+			}
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Test creating an inactive repository
+			{
+				Config: config("inactive", "1.0"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_repository.test", "state", "inactive"),
+					checkServer(graphdb.RepositoryStateInactive, 0),
+				),
+			},
+			// Test starting it, which does not restart it again for the new trigger
+			{
+				Config: config("running", "1.1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_repository.test", "state", "running"),
+					checkServer(graphdb.RepositoryStateRunning, 1),
+				),
+			},
+			// Test that changing a trigger restarts the repository
+			{
+				Config: config("running", "1.2"),
+				Check:  checkServer(graphdb.RepositoryStateRunning, 2),
+			},
+			// Test that a repository stopped out-of-band is started again
+			{
+				PreConfig: func() {
+					server.SetRepositoryState("TestRepo", graphdb.RepositoryStateInactive)
+				},
+				Config: config("running", "1.2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_repository.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: checkServer(graphdb.RepositoryStateRunning, 3),
+			},
+		},
+	})
+}
+
+func TestRepositoryResourceStartup(t *testing.T) {
+	interval := repositoryPollInterval
+	repositoryPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { repositoryPollInterval = interval })
+	server := newTestServer(t, graphdbtest.WithRepositoryStartup(3))
+	config := func(name string, timeout string) string {
+		return testProviderConfig(server) + fmt.Sprintf(`
+resource "graphdb_repository" "test" {
+  name = %q
+  settings = {}
+  timeouts = {
+    create = %q
+  }
+}
+`, name, timeout)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Test that creation waits for the repository to be running
+			{
+				Config: config("TestRepo", "1m"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_repository.test", "state", "running"),
+					resource.TestCheckResourceAttr("graphdb_repository.test", "timeouts.create", "1m"),
+				),
+			},
+		},
+	})
+}
+
+func TestRepositoryResourceStartupTimeout(t *testing.T) {
+	interval := repositoryPollInterval
+	repositoryPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { repositoryPollInterval = interval })
+	server := newTestServer(t, graphdbtest.WithRepositoryStartup(1000000))
+	config := testProviderConfig(server) + `
+resource "graphdb_repository" "test" {
+  name = "TestRepo"
+  settings = {}
+  timeouts = {
+    create = "1s"
+  }
+}
+`
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Test that a repository which is slow to start is kept as created, rather than tainted and replaced
+			{
+				Config: config,
+				Check: func(state *terraform.State) error {
+					rs, ok := state.RootModule().Resources["graphdb_repository.test"]
+					if !ok {
+						return fmt.Errorf("Expected the repository to be in state")
+					}
+					if rs.Primary.ID != "TestRepo" || rs.Primary.Tainted {
+						return fmt.Errorf("Expected the repository to be tracked without being tainted. Got: %+v", rs.Primary)
+					}
+					if _, ok := server.Repository("TestRepo"); !ok {
+						return fmt.Errorf("Expected the repository to exist")
+					}
+					return nil
+				},
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestRepositoryResourceDeletionProtection(t *testing.T) {
+	server := newTestServer(t)
+	config := func(protected bool, ruleset string) string {
+		return testProviderConfig(server) + fmt.Sprintf(`
+resource "graphdb_repository" "test" {
+  name                = "TestRepo"
+  deletion_protection = %t
+  settings = {
+    ruleset = %q
+  }
+}
+`, protected, ruleset)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(true, "owl-horst-optimized"),
+				Check:  resource.TestCheckResourceAttr("graphdb_repository.test", "deletion_protection", "true"),
+			},
+			// Test that a replacement cannot delete the repository
+			{
+				Config:      config(true, "rdfsplus-optimized"),
+				ExpectError: regexp.MustCompile(`deletion_protection enabled`),
+			},
+			// Test that disabling protection is applied in place
+			{
+				Config: config(false, "owl-horst-optimized"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("graphdb_repository.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: func(_ *terraform.State) error {
+					if repo, ok := server.Repository("TestRepo"); !ok || repo.Params["ruleset"].Value != "owl-horst-optimized" {
+						return fmt.Errorf("Expected the protected repository to be kept. Got: %+v", repo)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestRepositoryResourceDataLossWarning(t *testing.T) {
+	ctx := context.Background()
+	server := graphdbtest.NewServer()
+	defer server.Close()
+	client, err := graphdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.CreateRepository(ctx, strings.NewReader(graphdb.FormatRepositoryConfig(graphdb.Repository{ID: "TestRepo"}))); err != nil {
+		t.Fatal(err)
+	}
+	server.SetRepositorySize("TestRepo", 100, 20)
+
+	r := &RepositoryResource{client: client}
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+	stateType, ok := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	if !ok {
+		t.Fatal("Expected the schema to be an object")
+	}
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range stateType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	values["id"] = tftypes.NewValue(tftypes.String, "TestRepo")
+	values["name"] = tftypes.NewValue(tftypes.String, "TestRepo")
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(stateType, values)}
+
+	renamed := map[string]tftypes.Value{}
+	for name, value := range values {
+		renamed[name] = value
+	}
+	renamed["name"] = tftypes.NewValue(tftypes.String, "Renamed")
+	plans := map[string]tfsdk.Plan{
+		"destroyed": {Schema: schemaResp.Schema, Raw: tftypes.NewValue(stateType, nil)},
+		"replaced":  {Schema: schemaResp.Schema, Raw: tftypes.NewValue(stateType, renamed)},
+	}
+	for action, plan := range plans {
+		resp := &fwresource.ModifyPlanResponse{Plan: plan}
+		r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{State: state, Plan: plan}, resp)
+		if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+			t.Fatalf("Expected a single warning when the repository is %s. Got: %v", action, resp.Diagnostics)
+		}
+		want := "holds 120 statements (100 explicit, 20 inferred), which will be deleted when it is " + action
+		if detail := resp.Diagnostics.Warnings()[0].Detail(); !strings.Contains(detail, want) {
+			t.Fatalf("Unexpected warning when the repository is %s: %s", action, detail)
+		}
+	}
+
+	// Empty repositories are not worth a warning
+	server.SetRepositorySize("TestRepo", 0, 0)
+	resp := &fwresource.ModifyPlanResponse{Plan: plans["destroyed"]}
+	r.ModifyPlan(ctx, fwresource.ModifyPlanRequest{State: state, Plan: plans["destroyed"]}, resp)
+	if len(resp.Diagnostics) != 0 {
+		t.Fatalf("Expected no warning for an empty repository. Got: %v", resp.Diagnostics)
+	}
+}
+
+func TestRepositoryResourceInvalidConfig(t *testing.T) {
+	server := newTestServer(t)
+	config := func(turtle string) string {
+		return testProviderConfig(server) + fmt.Sprintf(`
+resource "graphdb_repository" "test" {
+  name = "TestRepo"
+  config = <<EOT
+%sEOT
+}
+`, turtle)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config(`@prefix rep: <http://www.openrdf.org/config/repository#>.
+
+[] a rep:Repository ;
+    rep:repositoryID "OtherRepo" ;
+    rep:repositoryImpl [ rep:repositoryType "graphdb:SailRepository" ] .
+`),
+				ExpectError: regexp.MustCompile(`rep:repositoryID to "OtherRepo" on line 4`),
+			},
+			{
+				Config: config(`@prefix rep: <http://www.openrdf.org/config/repository#>.
+
+[] a rep:Repository ;
+    rep:repositoryID "TestRepo" ;
+    rdfs:label "Test" .
+`),
+				ExpectError: regexp.MustCompile(`line 5, column 5:\s+undefined prefix "rdfs"`),
+			},
+			{
+				Config: config(`@prefix rep: <http://www.openrdf.org/config/repository#>.
+
+[] a rep:Repository ;
+    rep:repositoryID "TestRepo" .
+`),
+				ExpectError: regexp.MustCompile(`must set rep:repositoryType`),
+			},
+		},
+	})
+	if _, ok := server.Repository("TestRepo"); ok {
+		t.Fatal("Expected no repository to be created")
+	}
+}
+
+func TestRepositoryResourceUnsupportedType(t *testing.T) {
+	server := newTestServer(t, graphdbtest.WithVersion("9.11.0", "free"))
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig(server) + `
+resource "graphdb_repository" "test" {
+  name = "Federation"
+  config = <<EOT
+@prefix rep: <http://www.openrdf.org/config/repository#>.
+
+[] a rep:Repository ;
+    rep:repositoryID "Federation" ;
+    rep:repositoryImpl [
+        rep:repositoryType "graphdb:FedXRepository"
+    ] .
+EOT
+}
+`,
+				ExpectError: regexp.MustCompile(`FedX federation \(requires GraphDB 10.0.0 or later\)`),
+			},
+		},
+	})
+}
+
+func TestImmutableChanges(t *testing.T) {
+	// The live repository as returned by GraphDB, keyed in camelCase
+	var live graphdb.Repository
+	err := json.Unmarshal([]byte(`{
+		"id": "TestRepo",
+		"type": "graphdb",
+		"params": {
+			"ruleset": {"name": "ruleset", "value": "rdfsplus-optimized"},
+			"entityIdSize": {"name": "entityIdSize", "value": "32"},
+			"queryTimeout": {"name": "queryTimeout", "value": "0"}
+		}
+	}`), &live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := func(entityIDSize string) graphdb.Repository {
+		repo, err := graphdb.ParseRepositoryConfig(fmt.Sprintf(`
+@prefix rep: <http://www.openrdf.org/config/repository#>.
+@prefix sr: <http://www.openrdf.org/config/repository/sail#>.
+@prefix graphdb: <http://www.ontotext.com/config/graphdb#>.
+
+[] a rep:Repository ;
+    rep:repositoryID "TestRepo" ;
+    rep:repositoryImpl [
+        rep:repositoryType "graphdb:SailRepository" ;
+        sr:sailImpl [ graphdb:ruleset "rdfsplus-optimized" ; graphdb:entity-id-size %q ; graphdb:query-timeout "30" ]
+    ].
+`, entityIDSize))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo
+	}
+
+	if changed := immutableChanges(live, config("32")); len(changed) != 0 {
+		t.Fatalf("Expected only mutable parameters to change. Got: %v", changed)
+	}
+	if changed := immutableChanges(live, config("40")); len(changed) != 1 || changed[0] != "entityIdSize" {
+		t.Fatalf("Expected the entity ID size to change. Got: %v", changed)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
+)
+
+// repositorySettingParams maps the attributes of the settings block to the GraphDB repository parameters they set,
+// keyed as in the JSON form of a repository which they are refreshed from and updated with.
+var repositorySettingParams = map[string]string{
+	"ruleset":                   "ruleset",
+	"base_url":                  "baseURL",
+	"entity_index_size":         "entityIndexSize",
+	"entity_id_size":            "entityIdSize",
+	"disable_same_as":           "disableSameAs",
+	"check_for_inconsistencies": "checkForInconsistencies",
+	"query_timeout":             "queryTimeout",
+	"query_limit_results":       "queryLimitResults",
+	"read_only":                 "readOnly",
+	"enable_context_index":      "enableContextIndex",
+	"enable_literal_index":      "enableLiteralIndex",
+	"enable_fts_index":          "enableFtsIndex",
+	"fts_indexes":               "ftsIndexes",
+	"fts_string_literals_index": "ftsStringLiteralsIndex",
+	"fts_iris_index":            "ftsIrisIndex",
+}
+
+func repositorySettingsSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Description: "Parameters of a GraphDB repository, rendered into its configuration. Settings which are not set use the GraphDB defaults," +
+			" except that removing a setting which affects storage keeps its current value. Conflicts with config.",
+		Attributes: map[string]schema.Attribute{
+			"ruleset": schema.StringAttribute{
+				Optional:    true,
+				Description: "Ruleset used for inference, either a predefined one such as rdfsplus-optimized or the path of a custom .pie file. Changing it recreates the repository.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"base_url": schema.StringAttribute{
+				Optional:    true,
+				Description: "Default namespace for the repository.",
+			},
+			"entity_index_size": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Initial size of the entity index.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"entity_id_size": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Size in bits of entity identifiers, either 32 or 40. Changing it recreates the repository.",
+				Validators: []validator.Int64{
+					int64validator.OneOf(32, 40),
+				},
+			},
+			"disable_same_as": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Disable the owl:sameAs optimisation. Changing it recreates the repository.",
+			},
+			"check_for_inconsistencies": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Check for inconsistencies defined by the ruleset.",
+			},
+			"query_timeout": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of seconds after which queries are stopped, 0 for no limit.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"query_limit_results": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of results of a query, 0 for no limit.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"read_only": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Make the repository read-only.",
+			},
+			"enable_context_index": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Build an index of named graphs. Changing it recreates the repository.",
+			},
+			"enable_literal_index": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Build an index of literals, to speed up queries filtering on them.",
+			},
+			"enable_fts_index": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Enable the full-text search index.",
+			},
+			"fts_indexes": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Full-text search indexes to build, by language, e.g. default and iri.",
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+			"fts_string_literals_index": schema.StringAttribute{
+				Optional:    true,
+				Description: "Full-text search index used for string literals without a language tag.",
+			},
+			"fts_iris_index": schema.StringAttribute{
+				Optional:    true,
+				Description: "Full-text search index used for IRIs, or none.",
+			},
+		},
+	}
+}
+
+// settingsParams returns the repository parameters for the attributes of a settings block which are set, given the
+// parameters set by each attribute, such as repositorySettingParams.
+func settingsParams(settings types.Object, attributeParams map[string]string) map[string]graphdb.RepositoryParam {
+	params := map[string]graphdb.RepositoryParam{}
+	if settings.IsNull() || settings.IsUnknown() {
+		return params
+	}
+	for attribute, value := range settings.Attributes() {
+		name, ok := attributeParams[attribute]
+		if !ok || value.IsNull() || value.IsUnknown() {
+			continue
+		}
+		var s string
+		switch v := value.(type) {
+		case types.String:
+			s = v.ValueString()
+		case types.Int64:
+			s = strconv.FormatInt(v.ValueInt64(), 10)
+		case types.Bool:
+			s = strconv.FormatBool(v.ValueBool())
+		case types.List:
+			var members []string
+			for _, element := range v.Elements() {
+				if member, ok := element.(types.String); ok {
+					members = append(members, member.ValueString())
+				}
+			}
+			s = strings.Join(members, ", ")
+		default:
+			continue
+		}
+		params[name] = graphdb.RepositoryParam{Name: name, Value: s}
+	}
+	return params
+}
+
+// refreshSettings updates the attributes of a settings block which are set with the values of the repository
+// parameters, so that changes made outside of Terraform are detected. Parameters missing from params, or with values
+// which cannot be converted, are left unchanged.
+func refreshSettings(ctx context.Context, settings types.Object, attributeParams map[string]string, params map[string]graphdb.RepositoryParam, diags *diag.Diagnostics) types.Object {
+	if settings.IsNull() || settings.IsUnknown() {
+		return settings
+	}
+
+	attributes := map[string]attr.Value{}
+	for attribute, value := range settings.Attributes() {
+		attributes[attribute] = value
+		param, ok := params[attributeParams[attribute]]
+		if !ok || value.IsNull() {
+			continue
+		}
+		switch value.(type) {
+		case types.String:
+			attributes[attribute] = types.StringValue(param.Value)
+		case types.Int64:
+			if n, err := strconv.ParseInt(param.Value, 10, 64); err == nil {
+				attributes[attribute] = types.Int64Value(n)
+			}
+		case types.Bool:
+			if b, err := strconv.ParseBool(param.Value); err == nil {
+				attributes[attribute] = types.BoolValue(b)
+			}
+		case types.List:
+			members := []attr.Value{}
+			for _, member := range strings.Split(param.Value, ",") {
+				if member = strings.TrimSpace(member); member != "" {
+					members = append(members, types.StringValue(member))
+				}
+			}
+			list, d := types.ListValue(types.StringType, members)
+			diags.Append(d...)
+			attributes[attribute] = list
+		}
+	}
+
+	refreshed, d := types.ObjectValue(settings.AttributeTypes(ctx), attributes)
+	diags.Append(d...)
+	return refreshed
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
 )
 
 var (
@@ -15,7 +16,7 @@ var (
 )
 
 type userDataSource struct {
-	client *Client
+	client graphdb.SecurityAPI
 }
 
 type usersDataSourceModel struct {
@@ -41,7 +42,7 @@ func (d *userDataSource) Configure(ctx context.Context, req datasource.Configure
 		return
 	}
 
-	client, ok := req.ProviderData.(*Client)
+	client, ok := req.ProviderData.(graphdb.SecurityAPI)
 	if !ok {
 		unexpectedDataSourceConfigureType(ctx, req, resp)
 	}
@@ -43,3 +43,37 @@ data "graphdb_users" "users" {}
 		},
 	})
 }
+
+func TestUserDataSource(t *testing.T) {
+	server := newTestServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Setup users
+			{
+				Config: testProviderConfig(server) + `
+resource "graphdb_user" "user1" {
+  username = "DSUser1"
+  role = "user"
+}
+
+resource "graphdb_user" "manager1" {
+  username = "DSManager1"
+  password = "Hello1"
+  role = "repo-manager"
+}
+`,
+			},
+			{
+				Config: testProviderConfig(server) + `
+data "graphdb_users" "users" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// The admin user already exists
+					resource.TestCheckResourceAttr("data.graphdb_users.users", "users.#", "3"),
+				),
+			},
+		},
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
 )
 
 var (
@@ -22,7 +23,7 @@ var (
 )
 
 type UserResource struct {
-	client *Client
+	client graphdb.SecurityAPI
 }
 
 type UserResourceModel struct {
@@ -41,9 +42,10 @@ func (r *UserResource) Configure(ctx context.Context, req resource.ConfigureRequ
 		return
 	}
 
-	client, ok := req.ProviderData.(*Client)
+	client, ok := req.ProviderData.(graphdb.SecurityAPI)
 	if !ok {
 		ProviderDataError(req.ProviderData, &resp.Diagnostics)
+		return
 	}
 
 	r.client = client
@@ -90,7 +92,7 @@ func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, r
 	}
 	username := plan.Username.ValueString()
 
-	request := userCreateRequest{
+	request := graphdb.User{
 		Username: username,
 		Password: plan.Password.ValueString(),
 		Authorities: []string{
@@ -131,8 +133,14 @@ func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	username := state.ID.ValueString()
 	tflog.Debug(ctx, "Reading user", map[string]any{"username": username})
 	err := r.doRead(ctx, username, &state)
+	if graphdb.IsNotFound(err) {
+		tflog.Warn(ctx, "User no longer exists, removing from state", map[string]any{"username": username})
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to read user", fmt.Sprintf("Unable to read user. Unexpected error: %s", err.Error()))
+		return
 	}
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -150,7 +158,7 @@ func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	}
 
 	username := plan.Username.ValueString()
-	request := userCreateRequest{
+	request := graphdb.User{
 		Username:    username,
 		Password:    plan.Password.ValueString(),
 		Authorities: []string{roleToAuthority(plan.Role.ValueString())},
@@ -187,6 +195,10 @@ func (r *UserResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	username := state.ID.ValueString()
 	tflog.Debug(ctx, "Deleting user", map[string]any{"username": username})
 	err := r.client.DeleteUser(ctx, username)
+	if graphdb.IsNotFound(err) {
+		tflog.Debug(ctx, "User already deleted", map[string]any{"username": username})
+		return
+	}
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to delete user", fmt.Sprintf("Could not delete user. Unexpected error: %s", err.Error()))
 	}
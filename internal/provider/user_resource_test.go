@@ -4,10 +4,15 @@
 package provider
 
 import (
+	"context"
 	"regexp"
 	"testing"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
 )
 
 func TestAccUserResource(t *testing.T) {
@@ -61,3 +66,110 @@ func TestAccUserResource(t *testing.T) {
 		},
 	})
 }
+
+func TestUserResource(t *testing.T) {
+	server := newTestServer(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Test create and read
+			{
+				Config: testProviderConfig(server) + `
+resource "graphdb_user" "test" {
+  username = "TestUser"
+  password = "SuperSecret"
+  role = "user"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_user.test", "username", "TestUser"),
+					resource.TestCheckResourceAttr("graphdb_user.test", "role", "user")),
+			},
+			// Test import
+			{
+				ResourceName:            "graphdb_user.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+			// Test update and read
+			{
+				Config: testProviderConfig(server) + `
+resource "graphdb_user" "test" {
+  username = "TestUser"
+  password = "SuperSecret"
+  role = "repo-manager"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_user.test", "role", "repo-manager")),
+			},
+			// Test that a user deleted out-of-band is recreated
+			{
+				PreConfig: func() {
+					server.RemoveUser("TestUser")
+				},
+				Config: testProviderConfig(server) + `
+resource "graphdb_user" "test" {
+  username = "TestUser"
+  password = "SuperSecret"
+  role = "repo-manager"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("graphdb_user.test", "role", "repo-manager")),
+			},
+		},
+	})
+}
+
+func userResourceState(t *testing.T, r *UserResource, username string) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+	schemaResp := &fwresource.SchemaResponse{}
+	r.Schema(ctx, fwresource.SchemaRequest{}, schemaResp)
+
+	return tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), map[string]tftypes.Value{
+			"id":       tftypes.NewValue(tftypes.String, username),
+			"username": tftypes.NewValue(tftypes.String, username),
+			"password": tftypes.NewValue(tftypes.String, nil),
+			"role":     tftypes.NewValue(tftypes.String, "user"),
+		}),
+	}
+}
+
+func TestUserResourceRead(t *testing.T) {
+	r := &UserResource{client: newMockSecurityAPI(graphdb.User{Username: "TestUser", Authorities: []string{"ROLE_REPO_MANAGER"}})}
+	state := userResourceState(t, r, "TestUser")
+
+	resp := &fwresource.ReadResponse{State: state}
+	r.Read(context.Background(), fwresource.ReadRequest{State: state}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	var model UserResourceModel
+	if diags := resp.State.Get(context.Background(), &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if model.Role.ValueString() != "repo-manager" {
+		t.Fatalf("Expected role to be refreshed. Got: %s", model.Role.ValueString())
+	}
+}
+
+func TestUserResourceReadRemovesDeletedUser(t *testing.T) {
+	r := &UserResource{client: newMockSecurityAPI()}
+	state := userResourceState(t, r, "DeletedUser")
+
+	resp := &fwresource.ReadResponse{State: state}
+	r.Read(context.Background(), fwresource.ReadRequest{State: state}, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	if !resp.State.Raw.IsNull() {
+		t.Fatal("Expected user deleted out-of-band to be removed from state")
+	}
+}
@@ -2,15 +2,20 @@ package provider
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/nickrobison/terraform-provider-graphdb/graphdb"
 )
 
 func ProviderDataError(data any, diags *diag.Diagnostics) {
 	diags.AddError(
 		"Unexpected Resource Configure Type",
-		fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", data))
+		fmt.Sprintf("Expected *graphdb.Client, got: %T. Please report this issue to the provider developers.", data))
 }
 
 func roleToAuthority(role string) string {
@@ -24,3 +29,51 @@ func authorityToRole(authority string) (string, error) {
 	}
 	return strings.ToLower(strings.Replace(split[1], "_", "-", 1)), nil
 }
+
+// stringValueOrEnv returns the configured value, falling back to the given environment variable when it is null.
+func stringValueOrEnv(value types.String, env string) string {
+	if !value.IsNull() {
+		return value.ValueString()
+	}
+	return os.Getenv(env)
+}
+
+// boolValueOrEnv returns the configured value, falling back to the given environment variable when it is null.
+// An environment variable which is not a valid boolean is ignored with a warning.
+func boolValueOrEnv(value types.Bool, env string, attribute path.Path, diags *diag.Diagnostics) bool {
+	if !value.IsNull() {
+		return value.ValueBool()
+	}
+	v := os.Getenv(env)
+	if v == "" {
+		return false
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		diags.AddAttributeWarning(
+			attribute,
+			"Failed to parse "+env,
+			fmt.Sprintf("Error to parse value in "+env+" environment variable: %s\n"+
+				"So the variable is not used", err),
+		)
+		return false
+	}
+	return b
+}
+
+// requireFeature adds an error to diags if the connected server is known not to support the given feature.
+// If the server version could not be detected, the check is skipped and the server has the final say.
+func requireFeature(server graphdb.InfoAPI, feature graphdb.Feature, attribute path.Path, diags *diag.Diagnostics) {
+	if server == nil {
+		return
+	}
+	info, ok := server.ServerInfo()
+	if !ok || info.Supports(feature) {
+		return
+	}
+	diags.AddAttributeError(
+		attribute,
+		"Unsupported GraphDB feature",
+		fmt.Sprintf("This configuration uses %s, which is not available on the connected server (%s).", feature, info),
+	)
+}
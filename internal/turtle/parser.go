@@ -0,0 +1,630 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package turtle
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Parse parses a Turtle document.
+func Parse(document string) (*Graph, error) {
+	p := &parser{
+		input: document,
+		line:  1,
+		graph: &Graph{Prefixes: map[string]string{}},
+	}
+	if err := p.parseDocument(); err != nil {
+		return nil, err
+	}
+	return p.graph, nil
+}
+
+// parser is a recursive descent parser, working directly on the input rather than a token stream.
+type parser struct {
+	input string
+	pos   int
+	line  int
+	// lineStart is the offset of the start of the current line, used to compute columns
+	lineStart int
+
+	base   *url.URL
+	blanks int
+	graph  *Graph
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	column := utf8.RuneCountInString(p.input[p.lineStart:p.pos]) + 1
+	return &SyntaxError{Line: p.line, Column: column, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	r, _ := utf8.DecodeRuneInString(p.input[p.pos:])
+	return r
+}
+
+func (p *parser) next() rune {
+	r, size := utf8.DecodeRuneInString(p.input[p.pos:])
+	p.pos += size
+	if r == '\n' {
+		p.line++
+		p.lineStart = p.pos
+	}
+	return r
+}
+
+func (p *parser) hasPrefix(s string) bool {
+	return strings.HasPrefix(p.input[p.pos:], s)
+}
+
+// skip consumes whitespace and comments.
+func (p *parser) skip() {
+	for !p.eof() {
+		switch r := p.peek(); {
+		case r == '#':
+			for !p.eof() && p.peek() != '\n' {
+				p.next()
+			}
+		case unicode.IsSpace(r):
+			p.next()
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) expect(r rune) error {
+	p.skip()
+	if p.peek() != r {
+		return p.errorf("expected '%c', found %s", r, p.describeNext())
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) describeNext() string {
+	if p.eof() {
+		return "end of document"
+	}
+	return fmt.Sprintf("'%c'", p.peek())
+}
+
+// keyword reports whether the input continues with the given case-insensitive keyword, followed by a delimiter.
+func (p *parser) keyword(word string) bool {
+	if len(p.input)-p.pos < len(word) || !strings.EqualFold(p.input[p.pos:p.pos+len(word)], word) {
+		return false
+	}
+	rest := p.input[p.pos+len(word):]
+	if rest == "" {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(rest)
+	return !isNameChar(r)
+}
+
+func (p *parser) consume(n int) {
+	for i := 0; i < n; i++ {
+		p.next()
+	}
+}
+
+func (p *parser) parseDocument() error {
+	for {
+		p.skip()
+		if p.eof() {
+			return nil
+		}
+		if err := p.parseStatement(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *parser) parseStatement() error {
+	switch {
+	case p.hasPrefix("@prefix"):
+		p.consume(len("@prefix"))
+		if err := p.parsePrefix(); err != nil {
+			return err
+		}
+		return p.expect('.')
+	case p.hasPrefix("@base"):
+		p.consume(len("@base"))
+		if err := p.parseBase(); err != nil {
+			return err
+		}
+		return p.expect('.')
+	case p.keyword("PREFIX"):
+		p.consume(len("PREFIX"))
+		return p.parsePrefix()
+	case p.keyword("BASE"):
+		p.consume(len("BASE"))
+		return p.parseBase()
+	}
+
+	if err := p.parseTriples(); err != nil {
+		return err
+	}
+	return p.expect('.')
+}
+
+func (p *parser) parsePrefix() error {
+	p.skip()
+	start := p.pos
+	for !p.eof() && p.peek() != ':' {
+		if !isNameChar(p.peek()) {
+			return p.errorf("invalid prefix name")
+		}
+		p.next()
+	}
+	prefix := p.input[start:p.pos]
+	if err := p.expect(':'); err != nil {
+		return err
+	}
+	p.skip()
+	iri, err := p.parseIRIRef()
+	if err != nil {
+		return err
+	}
+	p.graph.Prefixes[prefix] = iri
+	return nil
+}
+
+func (p *parser) parseBase() error {
+	p.skip()
+	iri, err := p.parseIRIRef()
+	if err != nil {
+		return err
+	}
+	base, err := url.Parse(iri)
+	if err != nil {
+		return p.errorf("invalid base IRI %q", iri)
+	}
+	p.base = base
+	return nil
+}
+
+func (p *parser) parseTriples() error {
+	p.skip()
+	if p.peek() == '[' {
+		subject, err := p.parseBlankNodePropertyList()
+		if err != nil {
+			return err
+		}
+		// A blank node property list may stand alone as a statement
+		p.skip()
+		if p.peek() == '.' {
+			return nil
+		}
+		return p.parsePredicateObjectList(subject)
+	}
+
+	subject, err := p.parseSubject()
+	if err != nil {
+		return err
+	}
+	return p.parsePredicateObjectList(subject)
+}
+
+func (p *parser) parseSubject() (Term, error) {
+	p.skip()
+	switch p.peek() {
+	case '(':
+		return p.parseCollection()
+	case '"', '\'':
+		return Term{}, p.errorf("a literal cannot be the subject of a statement")
+	}
+	return p.parseResource()
+}
+
+func (p *parser) parsePredicateObjectList(subject Term) error {
+	for {
+		p.skip()
+		predicate, err := p.parseVerb()
+		if err != nil {
+			return err
+		}
+		if err := p.parseObjectList(subject, predicate); err != nil {
+			return err
+		}
+
+		p.skip()
+		if p.peek() != ';' {
+			return nil
+		}
+		// Any number of semicolons may follow, and the list may end after them
+		for p.peek() == ';' {
+			p.next()
+			p.skip()
+		}
+		if r := p.peek(); r == '.' || r == ']' || p.eof() {
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseVerb() (Term, error) {
+	if p.peek() == 'a' {
+		rest := p.input[p.pos+1:]
+		r, _ := utf8.DecodeRuneInString(rest)
+		if rest == "" || !isNameChar(r) && r != ':' {
+			p.next()
+			return NewIRI(RDF + "type"), nil
+		}
+	}
+	switch p.peek() {
+	case '[', '(', '"', '\'', '_':
+		return Term{}, p.errorf("expected a predicate, found %s", p.describeNext())
+	}
+	return p.parseIRI()
+}
+
+func (p *parser) parseObjectList(subject Term, predicate Term) error {
+	for {
+		p.skip()
+		line := p.line
+		object, err := p.parseObject()
+		if err != nil {
+			return err
+		}
+		p.graph.Triples = append(p.graph.Triples, Triple{Subject: subject, Predicate: predicate, Object: object, Line: line})
+
+		p.skip()
+		if p.peek() != ',' {
+			return nil
+		}
+		p.next()
+	}
+}
+
+func (p *parser) parseObject() (Term, error) {
+	p.skip()
+	switch r := p.peek(); {
+	case r == '[':
+		return p.parseBlankNodePropertyList()
+	case r == '(':
+		return p.parseCollection()
+	case r == '"' || r == '\'':
+		return p.parseRDFLiteral()
+	case r == '+' || r == '-' || r == '.' || (r >= '0' && r <= '9'):
+		return p.parseNumber()
+	case p.keyword("true") || p.keyword("false"):
+		value := "true"
+		if p.keyword("false") {
+			value = "false"
+		}
+		p.consume(len(value))
+		return Term{Kind: Literal, Value: value, Datatype: XSD + "boolean"}, nil
+	}
+	return p.parseResource()
+}
+
+// parseResource parses an IRI or a labelled blank node.
+func (p *parser) parseResource() (Term, error) {
+	if p.hasPrefix("_:") {
+		p.consume(2)
+		start := p.pos
+		for !p.eof() && isNameChar(p.peek()) {
+			p.next()
+		}
+		label := strings.TrimRight(p.input[start:p.pos], ".")
+		p.pos = start + len(label)
+		if label == "" {
+			return Term{}, p.errorf("missing blank node label")
+		}
+		return Term{Kind: BlankNode, Value: "b_" + label}, nil
+	}
+	return p.parseIRI()
+}
+
+func (p *parser) newBlankNode() Term {
+	p.blanks++
+	return Term{Kind: BlankNode, Value: "g" + strconv.Itoa(p.blanks)}
+}
+
+func (p *parser) parseBlankNodePropertyList() (Term, error) {
+	p.next() // [
+	node := p.newBlankNode()
+	p.skip()
+	if p.peek() == ']' {
+		p.next()
+		return node, nil
+	}
+	if err := p.parsePredicateObjectList(node); err != nil {
+		return Term{}, err
+	}
+	return node, p.expect(']')
+}
+
+func (p *parser) parseCollection() (Term, error) {
+	p.next() // (
+	var members []Term
+	var lines []int
+	for {
+		p.skip()
+		if p.eof() {
+			return Term{}, p.errorf("unterminated collection")
+		}
+		if p.peek() == ')' {
+			p.next()
+			break
+		}
+		lines = append(lines, p.line)
+		member, err := p.parseObject()
+		if err != nil {
+			return Term{}, err
+		}
+		members = append(members, member)
+	}
+
+	head := NewIRI(RDF + "nil")
+	for i := len(members) - 1; i >= 0; i-- {
+		node := p.newBlankNode()
+		p.graph.Triples = append(p.graph.Triples,
+			Triple{Subject: node, Predicate: NewIRI(RDF + "first"), Object: members[i], Line: lines[i]},
+			Triple{Subject: node, Predicate: NewIRI(RDF + "rest"), Object: head, Line: lines[i]},
+		)
+		head = node
+	}
+	return head, nil
+}
+
+// parseIRI parses an IRI reference or a prefixed name.
+func (p *parser) parseIRI() (Term, error) {
+	if p.peek() == '<' {
+		iri, err := p.parseIRIRef()
+		return NewIRI(iri), err
+	}
+
+	start := p.pos
+	for !p.eof() && p.peek() != ':' {
+		// A prefix cannot start with a dot, which would otherwise be read as part of it
+		if !isNameChar(p.peek()) || p.pos == start && p.peek() == '.' {
+			if p.pos == start {
+				return Term{}, p.errorf("expected an IRI, found %s", p.describeNext())
+			}
+			return Term{}, p.errorf("expected ':' in prefixed name %q", p.input[start:p.pos])
+		}
+		p.next()
+	}
+	if p.eof() {
+		return Term{}, p.errorf("expected an IRI, found end of document")
+	}
+	prefix := p.input[start:p.pos]
+	p.next() // :
+
+	var local strings.Builder
+	for !p.eof() {
+		r := p.peek()
+		if r == '\\' {
+			p.next()
+			local.WriteRune(p.next())
+			continue
+		}
+		if r == '%' && p.pos+2 < len(p.input) {
+			local.WriteString(p.input[p.pos : p.pos+3])
+			p.consume(3)
+			continue
+		}
+		if !isNameChar(r) && r != ':' {
+			break
+		}
+		// A local name cannot end with a dot, which terminates the statement instead
+		if r == '.' {
+			n, _ := utf8.DecodeRuneInString(p.input[p.pos+1:])
+			if !isNameChar(n) && n != ':' {
+				break
+			}
+		}
+		local.WriteRune(p.next())
+	}
+
+	namespace, ok := p.graph.Prefixes[prefix]
+	if !ok {
+		p.pos = start
+		return Term{}, p.errorf("undefined prefix %q", prefix)
+	}
+	return NewIRI(namespace + local.String()), nil
+}
+
+func (p *parser) parseIRIRef() (string, error) {
+	if p.peek() != '<' {
+		return "", p.errorf("expected an IRI, found %s", p.describeNext())
+	}
+	p.next()
+	var iri strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated IRI")
+		}
+		r := p.next()
+		switch {
+		case r == '>':
+			return p.resolve(iri.String()), nil
+		case r == '\\':
+			u, err := p.parseUnicodeEscape()
+			if err != nil {
+				return "", err
+			}
+			iri.WriteRune(u)
+		case unicode.IsSpace(r) || r == '<' || r == '"':
+			return "", p.errorf("invalid character %q in IRI", r)
+		default:
+			iri.WriteRune(r)
+		}
+	}
+}
+
+func (p *parser) resolve(iri string) string {
+	if p.base == nil {
+		return iri
+	}
+	ref, err := url.Parse(iri)
+	if err != nil || ref.IsAbs() {
+		return iri
+	}
+	return p.base.ResolveReference(ref).String()
+}
+
+func (p *parser) parseUnicodeEscape() (rune, error) {
+	var digits int
+	switch p.peek() {
+	case 'u':
+		digits = 4
+	case 'U':
+		digits = 8
+	default:
+		return 0, p.errorf("invalid escape sequence")
+	}
+	p.next()
+	if p.pos+digits > len(p.input) {
+		return 0, p.errorf("invalid unicode escape sequence")
+	}
+	n, err := strconv.ParseUint(p.input[p.pos:p.pos+digits], 16, 32)
+	if err != nil {
+		return 0, p.errorf("invalid unicode escape sequence")
+	}
+	p.consume(digits)
+	return rune(n), nil
+}
+
+func (p *parser) parseRDFLiteral() (Term, error) {
+	value, err := p.parseString()
+	if err != nil {
+		return Term{}, err
+	}
+	literal := Term{Kind: Literal, Value: value, Datatype: XSD + "string"}
+
+	switch {
+	case p.peek() == '@':
+		p.next()
+		start := p.pos
+		for !p.eof() && (isLetter(p.peek()) || p.peek() == '-' || (p.peek() >= '0' && p.peek() <= '9')) {
+			p.next()
+		}
+		if p.pos == start {
+			return Term{}, p.errorf("missing language tag")
+		}
+		literal.Language = strings.ToLower(p.input[start:p.pos])
+		literal.Datatype = RDF + "langString"
+	case p.hasPrefix("^^"):
+		p.consume(2)
+		datatype, err := p.parseIRI()
+		if err != nil {
+			return Term{}, err
+		}
+		literal.Datatype = datatype.Value
+	}
+	return literal, nil
+}
+
+func (p *parser) parseString() (string, error) {
+	quote := p.next()
+	long := strings.Repeat(string(quote), 2)
+	if p.hasPrefix(long) {
+		p.consume(2)
+		long = strings.Repeat(string(quote), 3)
+	} else {
+		long = ""
+	}
+
+	var value strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+		if long != "" && p.hasPrefix(long) {
+			p.consume(3)
+			return value.String(), nil
+		}
+		if r := p.peek(); long == "" && (r == '\n' || r == '\r') {
+			return "", p.errorf("unterminated string, use triple quotes for strings spanning lines")
+		}
+		r := p.next()
+		switch {
+		case long == "" && r == quote:
+			return value.String(), nil
+		case r == '\\':
+			escaped, err := p.parseStringEscape()
+			if err != nil {
+				return "", err
+			}
+			value.WriteRune(escaped)
+		default:
+			value.WriteRune(r)
+		}
+	}
+}
+
+func (p *parser) parseStringEscape() (rune, error) {
+	escapes := map[rune]rune{'t': '\t', 'b': '\b', 'n': '\n', 'r': '\r', 'f': '\f', '"': '"', '\'': '\'', '\\': '\\'}
+	if r, ok := escapes[p.peek()]; ok {
+		p.next()
+		return r, nil
+	}
+	return p.parseUnicodeEscape()
+}
+
+func (p *parser) parseNumber() (Term, error) {
+	start := p.pos
+	if r := p.peek(); r == '+' || r == '-' {
+		p.next()
+	}
+	datatype := XSD + "integer"
+	digits := func() int {
+		n := 0
+		for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+			p.next()
+			n++
+		}
+		return n
+	}
+
+	n := digits()
+	if p.peek() == '.' {
+		// A dot is only part of the number if digits follow, otherwise it ends the statement
+		r, _ := utf8.DecodeRuneInString(p.input[p.pos+1:])
+		if r >= '0' && r <= '9' {
+			p.next()
+			n += digits()
+			datatype = XSD + "decimal"
+		}
+	}
+	if r := p.peek(); n > 0 && (r == 'e' || r == 'E') {
+		p.next()
+		if r := p.peek(); r == '+' || r == '-' {
+			p.next()
+		}
+		if digits() == 0 {
+			return Term{}, p.errorf("invalid exponent")
+		}
+		datatype = XSD + "double"
+	}
+	if n == 0 {
+		p.pos = start
+		return Term{}, p.errorf("invalid number")
+	}
+	return Term{Kind: Literal, Value: p.input[start:p.pos], Datatype: datatype}, nil
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// isNameChar reports whether r may appear in a prefixed name.
+func isNameChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.' || r == 0xB7 ||
+		(r >= 0x300 && r <= 0x36F) || r == 0x203F || r == 0x2040
+}
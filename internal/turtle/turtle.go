@@ -0,0 +1,244 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package turtle parses RDF documents in Turtle syntax, such as GraphDB repository configurations.
+//
+// It exists so that repository configurations can be validated when planning, reporting syntax errors with their line
+// and column before anything is sent to GraphDB, and compared with the configurations GraphDB returns. It implements
+// the Turtle grammar (https://www.w3.org/TR/turtle/) closely enough for configuration files, and is not meant to be a
+// general RDF library.
+package turtle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Common namespaces.
+const (
+	RDF  = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	RDFS = "http://www.w3.org/2000/01/rdf-schema#"
+	XSD  = "http://www.w3.org/2001/XMLSchema#"
+)
+
+// TermKind distinguishes IRIs, blank nodes and literals.
+type TermKind int
+
+const (
+	IRI TermKind = iota
+	BlankNode
+	Literal
+)
+
+// Term is a node of an RDF graph.
+type Term struct {
+	Kind TermKind
+	// Value is the IRI, the blank node label or the lexical form of the literal
+	Value    string
+	Datatype string
+	Language string
+}
+
+// NewIRI returns an IRI term.
+func NewIRI(iri string) Term {
+	return Term{Kind: IRI, Value: iri}
+}
+
+// NewLiteral returns a plain string literal.
+func NewLiteral(value string) Term {
+	return Term{Kind: Literal, Value: value, Datatype: XSD + "string"}
+}
+
+// String formats the term in N-Triples syntax.
+func (t Term) String() string {
+	switch t.Kind {
+	case IRI:
+		return "<" + t.Value + ">"
+	case BlankNode:
+		return "_:" + t.Value
+	}
+	s := `"` + escapeString(t.Value) + `"`
+	if t.Language != "" {
+		return s + "@" + t.Language
+	}
+	if t.Datatype != "" && t.Datatype != XSD+"string" {
+		return s + "^^<" + t.Datatype + ">"
+	}
+	return s
+}
+
+// Triple is a single statement.
+type Triple struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+	// Line is the line of the document the object appears on, for error messages
+	Line int
+}
+
+func (t Triple) String() string {
+	return fmt.Sprintf("%s %s %s .", t.Subject, t.Predicate, t.Object)
+}
+
+// Graph is the set of statements in a document, along with the prefixes it declares.
+type Graph struct {
+	Triples  []Triple
+	Prefixes map[string]string
+}
+
+// Objects returns the objects of the statements with the given subject and predicate.
+func (g *Graph) Objects(subject Term, predicate string) []Term {
+	var objects []Term
+	for _, t := range g.Triples {
+		if t.Subject == subject && t.Predicate.Value == predicate {
+			objects = append(objects, t.Object)
+		}
+	}
+	return objects
+}
+
+// Object returns the object of the first statement with the given subject and predicate.
+func (g *Graph) Object(subject Term, predicate string) (Term, bool) {
+	for _, t := range g.Triples {
+		if t.Subject == subject && t.Predicate.Value == predicate {
+			return t.Object, true
+		}
+	}
+	return Term{}, false
+}
+
+// Subjects returns the subjects of the statements with the given predicate and object.
+func (g *Graph) Subjects(predicate string, object Term) []Term {
+	var subjects []Term
+	for _, t := range g.Triples {
+		if t.Predicate.Value == predicate && t.Object == object {
+			subjects = append(subjects, t.Subject)
+		}
+	}
+	return subjects
+}
+
+// Find returns the first statement with the given subject and predicate.
+func (g *Graph) Find(subject Term, predicate string) (Triple, bool) {
+	for _, t := range g.Triples {
+		if t.Subject == subject && t.Predicate.Value == predicate {
+			return t, true
+		}
+	}
+	return Triple{}, false
+}
+
+// List returns the members of the RDF collection starting at head, and false if head is not a well-formed collection.
+func (g *Graph) List(head Term) ([]Term, bool) {
+	var members []Term
+	for node := head; node != NewIRI(RDF+"nil"); {
+		first, ok := g.Object(node, RDF+"first")
+		if !ok {
+			return nil, false
+		}
+		rest, ok := g.Object(node, RDF+"rest")
+		if !ok {
+			return nil, false
+		}
+		members = append(members, first)
+		node = rest
+		if len(members) > len(g.Triples) {
+			// A cycle
+			return nil, false
+		}
+	}
+	return members, true
+}
+
+// Canonical returns the statements of the graph in N-Triples syntax, sorted, with blank nodes relabelled
+// according to their position, so that documents differing only in formatting, prefixes or statement order
+// produce the same result. Blank node labels are assigned by walking the graph from its named subjects, which is
+// sufficient for tree-shaped documents such as configurations, although not for arbitrary graphs.
+func (g *Graph) Canonical() []string {
+	labels := map[string]string{}
+	var walk func(subject Term, path string)
+	walk = func(subject Term, path string) {
+		children := []Triple{}
+		for _, t := range g.Triples {
+			if t.Subject == subject && t.Object.Kind == BlankNode {
+				children = append(children, t)
+			}
+		}
+		sort.SliceStable(children, func(i, j int) bool {
+			return children[i].Predicate.Value < children[j].Predicate.Value
+		})
+		for i, t := range children {
+			if _, ok := labels[t.Object.Value]; ok {
+				continue
+			}
+			label := fmt.Sprintf("%s.%d", path, i)
+			labels[t.Object.Value] = label
+			walk(t.Object, label)
+		}
+	}
+
+	roots := []Term{}
+	seen := map[Term]bool{}
+	referenced := map[string]bool{}
+	for _, t := range g.Triples {
+		if t.Object.Kind == BlankNode {
+			referenced[t.Object.Value] = true
+		}
+	}
+	for _, t := range g.Triples {
+		root := t.Subject.Kind == IRI || (t.Subject.Kind == BlankNode && !referenced[t.Subject.Value])
+		if root && !seen[t.Subject] {
+			seen[t.Subject] = true
+			roots = append(roots, t.Subject)
+		}
+	}
+	sort.SliceStable(roots, func(i, j int) bool {
+		return roots[i].Kind == IRI && roots[j].Kind != IRI
+	})
+	for i, root := range roots {
+		label := fmt.Sprintf("r%d", i)
+		if root.Kind == BlankNode {
+			labels[root.Value] = label
+		} else {
+			label = root.Value
+		}
+		walk(root, label)
+	}
+
+	relabel := func(t Term) Term {
+		if t.Kind == BlankNode {
+			if label, ok := labels[t.Value]; ok {
+				t.Value = label
+			}
+		}
+		return t
+	}
+	statements := make([]string, 0, len(g.Triples))
+	seenStatements := map[string]bool{}
+	for _, t := range g.Triples {
+		s := Triple{Subject: relabel(t.Subject), Predicate: t.Predicate, Object: relabel(t.Object)}.String()
+		if !seenStatements[s] {
+			seenStatements[s] = true
+			statements = append(statements, s)
+		}
+	}
+	sort.Strings(statements)
+	return statements
+}
+
+// SyntaxError is a Turtle syntax error.
+type SyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Msg)
+}
+
+func escapeString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return r.Replace(s)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package turtle
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const repositoryConfig = `
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#>.
+@prefix rep: <http://www.openrdf.org/config/repository#>.
+PREFIX graphdb: <http://www.ontotext.com/config/graphdb#>
+
+# A repository
+[] a rep:Repository ;
+    rep:repositoryID "TestRepo" ;
+    rdfs:label 'Test "repository"'@en ;
+    rep:repositoryImpl [
+        rep:repositoryType "graphdb:SailRepository" ;
+        graphdb:entity-index-size 10000000 ;
+        graphdb:read-only false ;
+        graphdb:fts-indexes ("default" "iri") ;
+        graphdb:base-URL <http://example.org/owlim#> ;
+        graphdb:imports """first
+second""" ;
+    ].
+`
+
+func TestParse(t *testing.T) {
+	g, err := Parse(repositoryConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repos := g.Subjects(RDF+"type", NewIRI("http://www.openrdf.org/config/repository#Repository"))
+	if len(repos) != 1 || repos[0].Kind != BlankNode {
+		t.Fatalf("Expected a single blank node repository. Got: %v", repos)
+	}
+	repo := repos[0]
+
+	id, ok := g.Find(repo, "http://www.openrdf.org/config/repository#repositoryID")
+	if !ok || id.Object != NewLiteral("TestRepo") || id.Line != 8 {
+		t.Fatalf("Unexpected repository ID: %v, on line %d", id.Object, id.Line)
+	}
+	label, _ := g.Object(repo, RDFS+"label")
+	if label.Value != `Test "repository"` || label.Language != "en" {
+		t.Fatalf("Unexpected label: %s", label)
+	}
+
+	impl, _ := g.Object(repo, "http://www.openrdf.org/config/repository#repositoryImpl")
+	graphdb := "http://www.ontotext.com/config/graphdb#"
+	objects := map[string]Term{
+		"entity-index-size": {Kind: Literal, Value: "10000000", Datatype: XSD + "integer"},
+		"read-only":         {Kind: Literal, Value: "false", Datatype: XSD + "boolean"},
+		"base-URL":          NewIRI("http://example.org/owlim#"),
+		"imports":           NewLiteral("first\nsecond"),
+	}
+	for name, want := range objects {
+		if got, _ := g.Object(impl, graphdb+name); got != want {
+			t.Fatalf("Unexpected value for %s. Wanted: %s. Got: %s", name, want, got)
+		}
+	}
+
+	head, _ := g.Object(impl, graphdb+"fts-indexes")
+	members, ok := g.List(head)
+	if want := []Term{NewLiteral("default"), NewLiteral("iri")}; !ok || !reflect.DeepEqual(members, want) {
+		t.Fatalf("Unexpected collection. Wanted: %v. Got: %v", want, members)
+	}
+}
+
+func TestParseSyntax(t *testing.T) {
+	documents := map[string][]string{
+		"@base <http://example.org/dir/> . <a> <b> <../c> .": {
+			"<http://example.org/dir/a> <http://example.org/dir/b> <http://example.org/c> .",
+		},
+		"@prefix : <urn:> . :s :p :a, :b ; :q 1.5, -2, 1e3 ;; .": {
+			"<urn:s> <urn:p> <urn:a> .",
+			"<urn:s> <urn:p> <urn:b> .",
+			`<urn:s> <urn:q> "1.5"^^<http://www.w3.org/2001/XMLSchema#decimal> .`,
+			`<urn:s> <urn:q> "-2"^^<http://www.w3.org/2001/XMLSchema#integer> .`,
+			`<urn:s> <urn:q> "1e3"^^<http://www.w3.org/2001/XMLSchema#double> .`,
+		},
+		`@prefix : <urn:> . _:x :p _:y . _:y :p "é\t"^^:t .`: {
+			"_:b_x <urn:p> _:b_y .",
+			`_:b_y <urn:p> "é\t"^^<urn:t> .`,
+		},
+		"@prefix : <urn:> . :s :p :o.": {
+			"<urn:s> <urn:p> <urn:o> .",
+		},
+		"@prefix : <urn:> . :s :p () .": {
+			"<urn:s> <urn:p> <http://www.w3.org/1999/02/22-rdf-syntax-ns#nil> .",
+		},
+	}
+
+	for document, want := range documents {
+		g, err := Parse(document)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %s", document, err)
+		}
+		var got []string
+		for _, triple := range g.Triples {
+			got = append(got, triple.String())
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Unexpected triples for %q. Wanted: %v. Got: %v", document, want, got)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	documents := map[string]string{
+		"@prefix rep: <urn:rep#> .\n[] rdfs:label \"x\" .":   `line 2, column 4: undefined prefix "rdfs"`,
+		"<urn:s> <urn:p> <urn:o>\n<urn:s> <urn:p> <urn:o> .": "line 2, column 1: expected '.', found '<'",
+		"<urn:s> <urn:p> \"value\n.":                         "line 1, column 23: unterminated string, use triple quotes for strings spanning lines",
+		"[ <urn:p> <urn:o> ;":                                "line 1, column 20: expected ']', found end of document",
+		"PREFIX : <urn:> .\n:s :p :o .":                      "line 1, column 17: expected an IRI, found '.'",
+		"\"s\" <urn:p> <urn:o> .":                            "line 1, column 1: a literal cannot be the subject of a statement",
+	}
+
+	for document, want := range documents {
+		_, err := Parse(document)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("Expected a syntax error for %q. Got: %v", document, err)
+		}
+		if err.Error() != want {
+			t.Fatalf("Unexpected error for %q. Wanted: %s. Got: %s", document, want, err)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	a, err := Parse(`
+@prefix rep: <http://www.openrdf.org/config/repository#> .
+@prefix g: <http://www.ontotext.com/config/graphdb#> .
+
+[] a rep:Repository ; rep:repositoryID "TestRepo" ;
+   rep:repositoryImpl [ g:ruleset "owl-horst" ; g:fts-indexes ("default" "iri") ] .
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Parse(`PREFIX graphdb: <http://www.ontotext.com/config/graphdb#>
+_:impl graphdb:fts-indexes ( "default"
+    "iri" ) ;
+  <http://www.ontotext.com/config/graphdb#ruleset> "owl-horst" .
+_:repo <http://www.openrdf.org/config/repository#repositoryImpl> _:impl ;
+  <http://www.openrdf.org/config/repository#repositoryID> "TestRepo" ;
+  a <http://www.openrdf.org/config/repository#Repository> .
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a.Canonical(), b.Canonical()) {
+		t.Fatalf("Expected equivalent documents to have the same canonical form.\n%v\n%v", a.Canonical(), b.Canonical())
+	}
+
+	b.Triples[0].Object = NewLiteral("other")
+	if reflect.DeepEqual(a.Canonical(), b.Canonical()) {
+		t.Fatal("Expected different documents to have different canonical forms")
+	}
+}